@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/qq1477959747/linetime/backend/config"
 	"github.com/qq1477959747/linetime/backend/internal/api"
 	"github.com/qq1477959747/linetime/backend/internal/database"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+	"github.com/qq1477959747/linetime/backend/internal/scheduler"
+	"github.com/qq1477959747/linetime/backend/internal/service"
 	"github.com/qq1477959747/linetime/backend/internal/storage"
 )
 
@@ -27,8 +31,17 @@ func main() {
 	}
 	log.Println("Redis 初始化完成")
 
+	// 初始化 MinIO
+	minioStorage, err := storage.NewMinIOStorage()
+	if err != nil {
+		log.Fatalf("初始化 MinIO 存储失败: %v", err)
+	}
+
+	// 启动后台任务
+	startScheduler(minioStorage)
+
 	// 设置路由
-	router := api.SetupRouter(database.GetDB())
+	router := api.SetupRouter(database.GetDB(), minioStorage)
 
 	// 启动服务器
 	addr := fmt.Sprintf(":%s", config.AppConfig.Server.Port)
@@ -37,3 +50,26 @@ func main() {
 		log.Fatalf("服务器启动失败: %v", err)
 	}
 }
+
+func startScheduler(minioStorage *storage.MinIOStorage) {
+	db := database.GetDB()
+	spaceRepo := repository.NewSpaceRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	spaceService := service.NewSpaceService(spaceRepo, userRepo, minioStorage, nil)
+	activityService := service.NewActivityService(repository.NewActivityRepository(db), spaceRepo)
+	tagService := service.NewTagService(repository.NewTagRepository(db), spaceRepo)
+	mentionService := service.NewMentionService(repository.NewMentionRepository(db), spaceRepo, service.NewSMTPEmailService())
+	eventService := service.NewEventService(repository.NewEventRepository(db), spaceRepo, repository.NewRevisionRepository(db), tagService, minioStorage, activityService, mentionService, userRepo)
+	timelineService := service.NewTimelineService(repository.NewEventRepository(db), spaceRepo, userRepo)
+	digestService := service.NewDigestService(userRepo, timelineService, minioStorage, service.NewSMTPEmailService())
+	anniversaryService := service.NewAnniversaryService(repository.NewAnniversaryRepository(db), spaceRepo, service.NewSMTPEmailService())
+
+	s := scheduler.New()
+	s.Every("purge-trashed-spaces", config.AppConfig.Trash.PurgeInterval, spaceService.PurgeExpiredSpaces)
+	s.Every("purge-trashed-events", config.AppConfig.Trash.PurgeInterval, eventService.PurgeExpiredEvents)
+	s.Every("publish-scheduled-events", config.AppConfig.Publish.Interval, eventService.PublishDueEvents)
+	s.Every("prune-event-revisions", config.AppConfig.Revision.PruneInterval, eventService.PruneRevisions)
+	s.Every("on-this-day-digest", config.AppConfig.Digest.Interval, digestService.SendOnThisDayDigests)
+	s.Every("anniversary-reminders", config.AppConfig.Anniversary.ReminderInterval, anniversaryService.SendReminders)
+	s.Start(context.Background())
+}
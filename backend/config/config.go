@@ -11,13 +11,19 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	MinIO    MinIOConfig
-	JWT      JWTConfig
-	Upload   UploadConfig
-	SMTP     SMTPConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	MinIO       MinIOConfig
+	JWT         JWTConfig
+	Upload      UploadConfig
+	SMTP        SMTPConfig
+	Trash       TrashConfig
+	Search      SearchConfig
+	Revision    RevisionConfig
+	Digest      DigestConfig
+	Anniversary AnniversaryConfig
+	Publish     PublishConfig
 }
 
 type ServerConfig struct {
@@ -66,6 +72,38 @@ type SMTPConfig struct {
 	Username string
 	Password string
 	From     string
+	AppURL   string // 前端访问地址，用于生成邮件中的链接
+}
+
+type TrashConfig struct {
+	SpaceRetentionDays int
+	EventRetentionDays int
+	PurgeInterval      time.Duration
+}
+
+type SearchConfig struct {
+	TSConfig   string // PostgreSQL 全文检索配置，如 simple、english 或安装了中文分词插件后的 chinese
+	CJKTrigram bool   // 查询包含中日韩文字时使用 pg_trgm 模糊匹配
+}
+
+type RevisionConfig struct {
+	RetentionDays int // 事件修订记录保留天数，0 表示不按时间清理
+	MaxPerEvent   int // 每个事件最多保留的修订数，0 表示不限
+	PruneInterval time.Duration
+}
+
+type DigestConfig struct {
+	Interval time.Duration // 检查是否需要发送每日邮件的间隔
+	SendHour int           // 每天几点（服务器本地时间）之后开始发送
+}
+
+type AnniversaryConfig struct {
+	DefaultRemindDays int // 新建纪念日默认提前几天提醒
+	ReminderInterval  time.Duration
+}
+
+type PublishConfig struct {
+	Interval time.Duration // 检查定时发布事件是否到期的间隔
 }
 
 var AppConfig *Config
@@ -117,6 +155,32 @@ func Load() {
 			Username: getEnv("SMTP_USERNAME"),
 			Password: getEnv("SMTP_PASSWORD"),
 			From:     getEnv("SMTP_FROM"),
+			AppURL:   getEnvWithDefault("APP_URL", "http://localhost:3000"),
+		},
+		Trash: TrashConfig{
+			SpaceRetentionDays: getEnvAsInt("SPACE_TRASH_RETENTION_DAYS", 30),
+			EventRetentionDays: getEnvAsInt("EVENT_TRASH_RETENTION_DAYS", 30),
+			PurgeInterval:      getEnvAsDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Search: SearchConfig{
+			TSConfig:   getEnvWithDefault("SEARCH_TS_CONFIG", "simple"),
+			CJKTrigram: getEnvAsBool("SEARCH_CJK_TRIGRAM", true),
+		},
+		Revision: RevisionConfig{
+			RetentionDays: getEnvAsInt("EVENT_REVISION_RETENTION_DAYS", 180),
+			MaxPerEvent:   getEnvAsInt("EVENT_REVISION_MAX_PER_EVENT", 50),
+			PruneInterval: getEnvAsDuration("EVENT_REVISION_PRUNE_INTERVAL", 24*time.Hour),
+		},
+		Digest: DigestConfig{
+			Interval: getEnvAsDuration("DIGEST_INTERVAL", time.Hour),
+			SendHour: getEnvAsInt("DIGEST_SEND_HOUR", 8),
+		},
+		Anniversary: AnniversaryConfig{
+			DefaultRemindDays: getEnvAsInt("ANNIVERSARY_REMIND_DAYS", 3),
+			ReminderInterval:  getEnvAsDuration("ANNIVERSARY_REMINDER_INTERVAL", time.Hour),
+		},
+		Publish: PublishConfig{
+			Interval: getEnvAsDuration("SCHEDULED_PUBLISH_INTERVAL", time.Minute),
 		},
 	}
 }
@@ -180,6 +244,37 @@ func getEnvAsInt(key string, defaultVal int) int {
 	return value
 }
 
+func getEnvWithDefault(key, defaultVal string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultVal
+}
+
+func getEnvAsBool(key string, defaultVal bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultVal
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultVal
+	}
+	return value
+}
+
+func getEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultVal
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return defaultVal
+	}
+	return value
+}
+
 func Validate() error {
 	if AppConfig.JWT.Secret == "" {
 		return fmt.Errorf("JWT_SECRET 不能为空")
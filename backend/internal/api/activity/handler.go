@@ -0,0 +1,66 @@
+package activity
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	activityService *service.ActivityService
+}
+
+func NewHandler(activityService *service.ActivityService) *Handler {
+	return &Handler{activityService: activityService}
+}
+
+// GetSpaceActivity 获取空间动态
+func (h *Handler) GetSpaceActivity(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	feed, err := h.activityService.GetSpaceActivity(spaceID, userID, c.Query("cursor"), limit)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, feed)
+}
+
+// MarkSeen 标记空间动态为已读
+func (h *Handler) MarkSeen(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	if err := h.activityService.MarkSeen(spaceID, userID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已标记为已读", nil)
+}
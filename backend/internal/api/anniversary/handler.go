@@ -0,0 +1,173 @@
+package anniversary
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	anniversaryService *service.AnniversaryService
+}
+
+func NewHandler(anniversaryService *service.AnniversaryService) *Handler {
+	return &Handler{anniversaryService: anniversaryService}
+}
+
+// GetAnniversaries 获取空间纪念日列表
+func (h *Handler) GetAnniversaries(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	anniversaries, err := h.anniversaryService.GetAnniversaries(spaceID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, anniversaries)
+}
+
+// GetAnniversary 获取纪念日的“第 N 天”和倒计时，可用 date 指定计算日期
+func (h *Handler) GetAnniversary(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	anniversaryID, err := uuid.Parse(c.Param("anniversary_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的纪念日ID")
+		return
+	}
+
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			response.BadRequest(c, "日期格式错误")
+			return
+		}
+		date = &parsed
+	}
+
+	item, err := h.anniversaryService.GetAnniversary(spaceID, anniversaryID, userID, date)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// CreateAnniversary 创建纪念日
+func (h *Handler) CreateAnniversary(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	var req service.CreateAnniversaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	item, err := h.anniversaryService.CreateAnniversary(spaceID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// UpdateAnniversary 修改纪念日
+func (h *Handler) UpdateAnniversary(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	anniversaryID, err := uuid.Parse(c.Param("anniversary_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的纪念日ID")
+		return
+	}
+
+	var req service.UpdateAnniversaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	item, err := h.anniversaryService.UpdateAnniversary(spaceID, anniversaryID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// DeleteAnniversary 删除纪念日
+func (h *Handler) DeleteAnniversary(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	anniversaryID, err := uuid.Parse(c.Param("anniversary_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的纪念日ID")
+		return
+	}
+
+	if err := h.anniversaryService.DeleteAnniversary(spaceID, anniversaryID, userID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
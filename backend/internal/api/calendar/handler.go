@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	calendarService *service.CalendarService
+}
+
+func NewHandler(calendarService *service.CalendarService) *Handler {
+	return &Handler{calendarService: calendarService}
+}
+
+// GetMonth 获取月历视图数据，year、month 默认为当前年月
+func (h *Handler) GetMonth(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	now := time.Now()
+	year, err := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(now.Year())))
+	if err != nil {
+		response.BadRequest(c, "无效的年份")
+		return
+	}
+	month, err := strconv.Atoi(c.DefaultQuery("month", strconv.Itoa(int(now.Month()))))
+	if err != nil {
+		response.BadRequest(c, "无效的月份")
+		return
+	}
+
+	calendar, err := h.calendarService.GetMonth(spaceID, userID, year, month)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, calendar)
+}
+
+// GetHeatmap 获取全年热力图数据，year 默认为当前年份
+func (h *Handler) GetHeatmap(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	year, err := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(time.Now().Year())))
+	if err != nil {
+		response.BadRequest(c, "无效的年份")
+		return
+	}
+
+	heatmap, err := h.calendarService.GetHeatmap(spaceID, userID, year)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, heatmap)
+}
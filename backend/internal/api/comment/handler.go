@@ -0,0 +1,136 @@
+package comment
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	commentService *service.CommentService
+}
+
+func NewHandler(commentService *service.CommentService) *Handler {
+	return &Handler{commentService: commentService}
+}
+
+// GetComments 分页获取事件评论
+func (h *Handler) GetComments(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.commentService.GetComments(eventID, userID, c.Query("cursor"), limit)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, page)
+}
+
+// CreateComment 发表评论或回复
+func (h *Handler) CreateComment(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	var req service.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(eventID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, comment)
+}
+
+// UpdateComment 修改评论
+func (h *Handler) UpdateComment(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的评论ID")
+		return
+	}
+
+	var req service.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(eventID, commentID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, comment)
+}
+
+// DeleteComment 删除评论
+func (h *Handler) DeleteComment(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的评论ID")
+		return
+	}
+
+	if err := h.commentService.DeleteComment(eventID, commentID, userID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
@@ -1,6 +1,10 @@
 package event
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -38,6 +42,7 @@ func (h *Handler) CreateEvent(c *gin.Context) {
 		return
 	}
 
+	setETag(c, event.Version)
 	response.Success(c, event)
 }
 
@@ -61,6 +66,7 @@ func (h *Handler) GetEventByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, event.Version)
 	response.Success(c, event)
 }
 
@@ -78,23 +84,31 @@ func (h *Handler) GetEventsBySpace(c *gin.Context) {
 		return
 	}
 
+	limit, _ := strconv.Atoi(c.Query("limit"))
 	req := service.QueryEventsRequest{
-		SpaceID: spaceID,
+		SpaceID:      spaceID,
+		Cursor:       c.Query("cursor"),
+		Limit:        limit,
+		IncludeTotal: c.Query("include_total") == "true",
 	}
 
 	// 解析查询参数
 	if startDateStr := c.Query("start_date"); startDateStr != "" {
 		startDate, err := time.Parse("2006-01-02", startDateStr)
-		if err == nil {
-			req.StartDate = &startDate
+		if err != nil {
+			response.BadRequest(c, "开始日期格式错误")
+			return
 		}
+		req.StartDate = &startDate
 	}
 
 	if endDateStr := c.Query("end_date"); endDateStr != "" {
 		endDate, err := time.Parse("2006-01-02", endDateStr)
-		if err == nil {
-			req.EndDate = &endDate
+		if err != nil {
+			response.BadRequest(c, "结束日期格式错误")
+			return
 		}
+		req.EndDate = &endDate
 	}
 
 	events, err := h.eventService.GetEventsBySpace(&req, userID)
@@ -126,12 +140,23 @@ func (h *Handler) UpdateEvent(c *gin.Context) {
 		return
 	}
 
+	// If-Match 优先于请求体中的 version
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, err := parseETag(ifMatch)
+		if err != nil {
+			response.BadRequest(c, "无效的 If-Match")
+			return
+		}
+		req.Version = &version
+	}
+
 	event, err := h.eventService.UpdateEvent(eventID, userID, &req)
 	if err != nil {
-		response.BadRequest(c, err.Error())
+		respondError(c, err)
 		return
 	}
 
+	setETag(c, event.Version)
 	response.Success(c, event)
 }
 
@@ -156,3 +181,234 @@ func (h *Handler) DeleteEvent(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "删除事件成功", nil)
 }
+
+// ReorderEventImages 调整事件图片顺序
+func (h *Handler) ReorderEventImages(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	var req service.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	event, err := h.eventService.ReorderEventImages(eventID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, event)
+}
+
+// DeleteEventImage 删除事件中的单张图片
+func (h *Handler) DeleteEventImage(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	imageID, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的图片ID")
+		return
+	}
+
+	event, err := h.eventService.DeleteEventImage(c.Request.Context(), eventID, imageID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "图片已删除", event)
+}
+
+// SetCoverImage 将图片设为事件封面
+func (h *Handler) SetCoverImage(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	imageID, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的图片ID")
+		return
+	}
+
+	event, err := h.eventService.SetCoverImage(eventID, imageID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, event)
+}
+
+// GetEventRevisions 获取事件修订历史
+func (h *Handler) GetEventRevisions(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	revisions, err := h.eventService.GetEventRevisions(eventID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, revisions)
+}
+
+// RestoreEventRevision 恢复到指定修订之前的内容
+func (h *Handler) RestoreEventRevision(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || revision < 1 {
+		response.BadRequest(c, "无效的修订号")
+		return
+	}
+
+	event, err := h.eventService.RestoreEventRevision(eventID, revision, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, event.Version)
+	response.SuccessWithMessage(c, "事件已恢复", event)
+}
+
+// respondError 版本冲突返回 409 并附带最新版本的 ETag，其他错误返回 400
+func respondError(c *gin.Context, err error) {
+	var conflict *service.VersionConflictError
+	if errors.As(err, &conflict) {
+		setETag(c, conflict.CurrentVersion)
+		response.Conflict(c, err.Error())
+		return
+	}
+	response.BadRequest(c, err.Error())
+}
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// parseETag 解析 If-Match 中的版本号，兼容弱校验前缀 W/
+func parseETag(value string) (int, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	return strconv.Atoi(strings.Trim(value, `"`))
+}
+
+// GetTrashedEvents 获取空间回收站中的事件
+func (h *Handler) GetTrashedEvents(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	events, err := h.eventService.GetTrashedEvents(spaceID, userID, c.Query("cursor"), limit)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, events)
+}
+
+// RestoreEvent 从回收站恢复事件
+func (h *Handler) RestoreEvent(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	event, err := h.eventService.RestoreEvent(eventID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	setETag(c, event.Version)
+	response.SuccessWithMessage(c, "事件已恢复", event)
+}
+
+// PermanentlyDeleteEvent 彻底删除回收站中的事件
+func (h *Handler) PermanentlyDeleteEvent(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	if err := h.eventService.PermanentlyDeleteEvent(c.Request.Context(), eventID, userID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "事件已彻底删除", nil)
+}
@@ -0,0 +1,48 @@
+package geo
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	mapService *service.MapService
+}
+
+func NewHandler(mapService *service.MapService) *Handler {
+	return &Handler{mapService: mapService}
+}
+
+// GetEventMap 获取地图聚合点，zoom 默认为 3
+func (h *Handler) GetEventMap(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	zoom, err := strconv.Atoi(c.DefaultQuery("zoom", "3"))
+	if err != nil {
+		response.BadRequest(c, "无效的缩放级别")
+		return
+	}
+
+	result, err := h.mapService.GetEventMap(spaceID, userID, c.Query("bbox"), zoom)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
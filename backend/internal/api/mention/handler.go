@@ -0,0 +1,53 @@
+package mention
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	mentionService *service.MentionService
+}
+
+func NewHandler(mentionService *service.MentionService) *Handler {
+	return &Handler{mentionService: mentionService}
+}
+
+// GetMyMentions 获取当前用户被 @ 提及的记录
+func (h *Handler) GetMyMentions(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	feed, err := h.mentionService.GetMyMentions(userID, c.Query("cursor"), limit)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, feed)
+}
+
+// MarkMentionsRead 将提及全部标记为已读
+func (h *Handler) MarkMentionsRead(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	if err := h.mentionService.MarkMentionsRead(userID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已全部标记为已读", nil)
+}
@@ -0,0 +1,69 @@
+package reaction
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	reactionService *service.ReactionService
+}
+
+func NewHandler(reactionService *service.ReactionService) *Handler {
+	return &Handler{reactionService: reactionService}
+}
+
+// AddReaction 添加表情回应
+func (h *Handler) AddReaction(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	var req service.AddReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	reactions, err := h.reactionService.AddReaction(eventID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, reactions)
+}
+
+// RemoveReaction 取消表情回应
+func (h *Handler) RemoveReaction(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的事件ID")
+		return
+	}
+
+	reactions, err := h.reactionService.RemoveReaction(eventID, userID, c.Param("emoji"))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, reactions)
+}
@@ -1,12 +1,22 @@
 package api
 
 import (
-	"log"
-
 	"github.com/gin-gonic/gin"
+	"github.com/qq1477959747/linetime/backend/internal/api/activity"
+	"github.com/qq1477959747/linetime/backend/internal/api/anniversary"
 	"github.com/qq1477959747/linetime/backend/internal/api/auth"
+	"github.com/qq1477959747/linetime/backend/internal/api/calendar"
+	"github.com/qq1477959747/linetime/backend/internal/api/comment"
 	"github.com/qq1477959747/linetime/backend/internal/api/event"
+	"github.com/qq1477959747/linetime/backend/internal/api/geo"
+	"github.com/qq1477959747/linetime/backend/internal/api/mention"
+	"github.com/qq1477959747/linetime/backend/internal/api/reaction"
+	"github.com/qq1477959747/linetime/backend/internal/api/search"
+	"github.com/qq1477959747/linetime/backend/internal/api/share"
 	"github.com/qq1477959747/linetime/backend/internal/api/space"
+	"github.com/qq1477959747/linetime/backend/internal/api/stats"
+	"github.com/qq1477959747/linetime/backend/internal/api/tag"
+	"github.com/qq1477959747/linetime/backend/internal/api/timeline"
 	"github.com/qq1477959747/linetime/backend/internal/api/upload"
 	"github.com/qq1477959747/linetime/backend/internal/api/user"
 	"github.com/qq1477959747/linetime/backend/internal/middleware"
@@ -16,7 +26,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRouter(db *gorm.DB) *gin.Engine {
+func SetupRouter(db *gorm.DB, minioStorage *storage.MinIOStorage) *gin.Engine {
 	r := gin.Default()
 
 	// 中间件
@@ -58,17 +68,82 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		{
 			spaceRepo := repository.NewSpaceRepository(db)
 			userRepoForSpace := repository.NewUserRepository(db)
-			spaceService := service.NewSpaceService(spaceRepo, userRepoForSpace)
+			activityRepo := repository.NewActivityRepository(db)
+			activityService := service.NewActivityService(activityRepo, spaceRepo)
+			spaceService := service.NewSpaceService(spaceRepo, userRepoForSpace, minioStorage, activityService)
 			spaceHandler := space.NewHandler(spaceService)
+			activityHandler := activity.NewHandler(activityService)
+			statsService := service.NewStatsService(repository.NewStatsRepository(db), spaceRepo)
+			statsHandler := stats.NewHandler(statsService)
+			calendarService := service.NewCalendarService(repository.NewEventRepository(db), spaceRepo)
+			calendarHandler := calendar.NewHandler(calendarService)
+			searchService := service.NewSearchService(repository.NewEventRepository(db), spaceRepo)
+			searchHandler := search.NewHandler(searchService)
+			mapService := service.NewMapService(repository.NewEventRepository(db), spaceRepo)
+			mapHandler := geo.NewHandler(mapService)
 
 			spacesGroup.POST("", spaceHandler.CreateSpace)                         // 创建空间
 			spacesGroup.GET("", spaceHandler.GetUserSpaces)                        // 获取用户的所有空间
+			spacesGroup.GET("/trash", spaceHandler.GetTrashedSpaces)               // 获取回收站中的空间
 			spacesGroup.GET("/:id", spaceHandler.GetSpaceByID)                     // 获取空间详情
-			spacesGroup.DELETE("/:id", spaceHandler.DeleteSpace)                   // 删除空间
+			spacesGroup.DELETE("/:id", spaceHandler.DeleteSpace)                   // 删除空间（移入回收站）
+			spacesGroup.POST("/:id/restore", spaceHandler.RestoreSpace)            // 从回收站恢复空间
+			spacesGroup.POST("/:id/archive", spaceHandler.ArchiveSpace)            // 归档空间
+			spacesGroup.POST("/:id/unarchive", spaceHandler.UnarchiveSpace)        // 取消归档
 			spacesGroup.POST("/:id/invite", spaceHandler.RefreshInviteCode)        // 刷新邀请码
 			spacesGroup.POST("/join/:code", spaceHandler.JoinSpace)                // 加入空间
 			spacesGroup.GET("/:id/members", spaceHandler.GetSpaceMembers)          // 获取空间成员
+			spacesGroup.PUT("/:id/members/me", spaceHandler.UpdateMyProfile)       // 修改自己在空间内的资料
 			spacesGroup.DELETE("/:id/members/:user_id", spaceHandler.RemoveMember) // 移除成员
+			spacesGroup.GET("/:id/activity", activityHandler.GetSpaceActivity)     // 获取空间动态
+			spacesGroup.POST("/:id/activity/seen", activityHandler.MarkSeen)       // 标记动态已读
+			spacesGroup.GET("/:id/stats", statsHandler.GetSpaceStats)              // 获取空间统计
+			spacesGroup.GET("/:id/calendar", calendarHandler.GetMonth)             // 获取月历视图
+			spacesGroup.GET("/:id/calendar/heatmap", calendarHandler.GetHeatmap)   // 获取年度热力图
+			spacesGroup.GET("/:id/events/search", searchHandler.SearchEvents)      // 搜索空间事件
+			spacesGroup.GET("/:id/events/map", mapHandler.GetEventMap)             // 获取地图聚合点
+
+			shareRepo := repository.NewShareRepository(db)
+			eventRepoForShare := repository.NewEventRepository(db)
+			shareService := service.NewShareService(shareRepo, spaceRepo, eventRepoForShare, minioStorage)
+			shareHandler := share.NewHandler(shareService)
+
+			spacesGroup.POST("/:id/shares", shareHandler.CreateShareLink)             // 创建分享链接
+			spacesGroup.GET("/:id/shares", shareHandler.GetShareLinks)                // 获取分享链接列表
+			spacesGroup.DELETE("/:id/shares/:share_id", shareHandler.RevokeShareLink) // 撤销分享链接
+
+			tagService := service.NewTagService(repository.NewTagRepository(db), spaceRepo)
+			tagHandler := tag.NewHandler(tagService)
+			eventService := service.NewEventService(repository.NewEventRepository(db), spaceRepo, repository.NewRevisionRepository(db), tagService, minioStorage, activityService, nil, nil)
+			eventHandler := event.NewHandler(eventService)
+
+			spacesGroup.GET("/:id/tags", tagHandler.ListTags)             // 获取空间标签
+			spacesGroup.POST("/:id/tags", tagHandler.CreateTag)           // 创建标签
+			spacesGroup.POST("/:id/tags/merge", tagHandler.MergeTags)     // 合并标签
+			spacesGroup.PUT("/:id/tags/:tag_id", tagHandler.UpdateTag)    // 修改标签
+			spacesGroup.DELETE("/:id/tags/:tag_id", tagHandler.DeleteTag) // 删除标签
+			spacesGroup.GET("/:id/trash", eventHandler.GetTrashedEvents)  // 获取事件回收站
+
+			anniversaryService := service.NewAnniversaryService(repository.NewAnniversaryRepository(db), spaceRepo, nil)
+			anniversaryHandler := anniversary.NewHandler(anniversaryService)
+
+			spacesGroup.GET("/:id/anniversaries", anniversaryHandler.GetAnniversaries)                     // 获取纪念日列表
+			spacesGroup.POST("/:id/anniversaries", anniversaryHandler.CreateAnniversary)                   // 创建纪念日
+			spacesGroup.GET("/:id/anniversaries/:anniversary_id", anniversaryHandler.GetAnniversary)       // 获取纪念日倒计时
+			spacesGroup.PUT("/:id/anniversaries/:anniversary_id", anniversaryHandler.UpdateAnniversary)    // 修改纪念日
+			spacesGroup.DELETE("/:id/anniversaries/:anniversary_id", anniversaryHandler.DeleteAnniversary) // 删除纪念日
+		}
+
+		// 公开分享路由（无需登录）
+		publicGroup := v1.Group("/public")
+		{
+			shareRepo := repository.NewShareRepository(db)
+			spaceRepo := repository.NewSpaceRepository(db)
+			eventRepo := repository.NewEventRepository(db)
+			shareService := service.NewShareService(shareRepo, spaceRepo, eventRepo, minioStorage)
+			shareHandler := share.NewHandler(shareService)
+
+			publicGroup.GET("/shares/:token", shareHandler.GetSharedContent) // 访问分享内容
 		}
 
 		// 事件路由
@@ -76,23 +151,49 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		{
 			eventRepo := repository.NewEventRepository(db)
 			spaceRepo := repository.NewSpaceRepository(db)
-			eventService := service.NewEventService(eventRepo, spaceRepo)
+			activityService := service.NewActivityService(repository.NewActivityRepository(db), spaceRepo)
+			tagService := service.NewTagService(repository.NewTagRepository(db), spaceRepo)
+			mentionService := service.NewMentionService(repository.NewMentionRepository(db), spaceRepo, service.NewSMTPEmailService())
+			eventService := service.NewEventService(eventRepo, spaceRepo, repository.NewRevisionRepository(db), tagService, minioStorage, activityService, mentionService, repository.NewUserRepository(db))
 			eventHandler := event.NewHandler(eventService)
+			commentService := service.NewCommentService(repository.NewCommentRepository(db), eventRepo, spaceRepo, activityService, mentionService)
+			commentHandler := comment.NewHandler(commentService)
+			reactionService := service.NewReactionService(repository.NewReactionRepository(db), eventRepo, spaceRepo)
+			reactionHandler := reaction.NewHandler(reactionService)
+
+			eventsGroup.POST("", eventHandler.CreateEvent)                                     // 创建事件
+			eventsGroup.GET("/:id", eventHandler.GetEventByID)                                 // 获取事件详情
+			eventsGroup.PUT("/:id", eventHandler.UpdateEvent)                                  // 更新事件
+			eventsGroup.DELETE("/:id", eventHandler.DeleteEvent)                               // 删除事件（移入回收站）
+			eventsGroup.POST("/:id/restore", eventHandler.RestoreEvent)                        // 从回收站恢复事件
+			eventsGroup.DELETE("/:id/permanent", eventHandler.PermanentlyDeleteEvent)          // 彻底删除事件
+			eventsGroup.PUT("/:id/images/order", eventHandler.ReorderEventImages)              // 调整图片顺序
+			eventsGroup.DELETE("/:id/images/:image_id", eventHandler.DeleteEventImage)         // 删除单张图片
+			eventsGroup.POST("/:id/images/:image_id/cover", eventHandler.SetCoverImage)        // 设为封面
+			eventsGroup.GET("/:id/revisions", eventHandler.GetEventRevisions)                  // 获取修订历史
+			eventsGroup.POST("/:id/revisions/:rev/restore", eventHandler.RestoreEventRevision) // 恢复修订
+			eventsGroup.GET("/spaces/:space_id", eventHandler.GetEventsBySpace)                // 获取空间事件列表
+			eventsGroup.GET("/:id/comments", commentHandler.GetComments)                       // 获取评论
+			eventsGroup.POST("/:id/comments", commentHandler.CreateComment)                    // 发表评论
+			eventsGroup.PUT("/:id/comments/:comment_id", commentHandler.UpdateComment)         // 修改评论
+			eventsGroup.DELETE("/:id/comments/:comment_id", commentHandler.DeleteComment)      // 删除评论
+			eventsGroup.POST("/:id/reactions", reactionHandler.AddReaction)                    // 添加表情回应
+			eventsGroup.DELETE("/:id/reactions/:emoji", reactionHandler.RemoveReaction)        // 取消表情回应
+		}
 
-			eventsGroup.POST("", eventHandler.CreateEvent)                            // 创建事件
-			eventsGroup.GET("/:id", eventHandler.GetEventByID)                        // 获取事件详情
-			eventsGroup.PUT("/:id", eventHandler.UpdateEvent)                         // 更新事件
-			eventsGroup.DELETE("/:id", eventHandler.DeleteEvent)                      // 删除事件
-			eventsGroup.GET("/spaces/:space_id", eventHandler.GetEventsBySpace)       // 获取空间事件列表
+		// 时间线路由
+		timelineGroup := v1.Group("/timeline", middleware.AuthMiddleware())
+		{
+			timelineService := service.NewTimelineService(repository.NewEventRepository(db), repository.NewSpaceRepository(db), repository.NewUserRepository(db))
+			timelineHandler := timeline.NewHandler(timelineService)
+
+			timelineGroup.GET("", timelineHandler.GetTimeline)              // 获取跨空间时间线
+			timelineGroup.GET("/on-this-day", timelineHandler.GetOnThisDay) // 那年今日
 		}
 
 		// 图片上传路由
 		uploadGroup := v1.Group("/upload", middleware.AuthMiddleware())
 		{
-			minioStorage, err := storage.NewMinIOStorage()
-			if err != nil {
-				log.Fatalf("初始化 MinIO 存储失败: %v", err)
-			}
 			uploadService := service.NewUploadService(minioStorage)
 			uploadHandler := upload.NewHandler(uploadService)
 
@@ -107,9 +208,15 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 			spaceRepo := repository.NewSpaceRepository(db)
 			userService := service.NewUserService(userRepo, spaceRepo)
 			userHandler := user.NewHandler(userService)
-
-			usersGroup.PUT("/default-space", userHandler.SetDefaultSpace)      // 设置默认空间
-			usersGroup.DELETE("/default-space", userHandler.ClearDefaultSpace) // 清除默认空间
+			mentionService := service.NewMentionService(repository.NewMentionRepository(db), spaceRepo, nil)
+			mentionHandler := mention.NewHandler(mentionService)
+
+			usersGroup.PUT("/default-space", userHandler.SetDefaultSpace)         // 设置默认空间
+			usersGroup.DELETE("/default-space", userHandler.ClearDefaultSpace)    // 清除默认空间
+			usersGroup.PUT("/me/digest", userHandler.SetDigest)                   // 订阅或取消每日邮件
+			usersGroup.PUT("/me/time-zone", userHandler.SetTimeZone)              // 设置默认时区
+			usersGroup.GET("/me/mentions", mentionHandler.GetMyMentions)          // 获取提及我的记录
+			usersGroup.POST("/me/mentions/read", mentionHandler.MarkMentionsRead) // 提及全部标记为已读
 		}
 	}
 
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	searchService *service.SearchService
+}
+
+func NewHandler(searchService *service.SearchService) *Handler {
+	return &Handler{searchService: searchService}
+}
+
+// SearchEvents 搜索空间内的事件
+func (h *Handler) SearchEvents(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	results, err := h.searchService.SearchEvents(spaceID, userID, c.Query("q"), c.Query("cursor"), limit)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, results)
+}
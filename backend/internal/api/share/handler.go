@@ -0,0 +1,129 @@
+package share
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	shareService *service.ShareService
+}
+
+func NewHandler(shareService *service.ShareService) *Handler {
+	return &Handler{shareService: shareService}
+}
+
+// CreateShareLink 创建分享链接
+func (h *Handler) CreateShareLink(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	var req service.CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	link, err := h.shareService.CreateShareLink(spaceID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, link)
+}
+
+// GetShareLinks 获取空间的分享链接列表
+func (h *Handler) GetShareLinks(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	links, err := h.shareService.GetShareLinks(spaceID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, links)
+}
+
+// RevokeShareLink 撤销分享链接
+func (h *Handler) RevokeShareLink(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	shareID, err := uuid.Parse(c.Param("share_id"))
+	if err != nil {
+		response.BadRequest(c, "无效的分享ID")
+		return
+	}
+
+	if err := h.shareService.RevokeShareLink(spaceID, shareID, userID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "分享已撤销", nil)
+}
+
+// GetSharedContent 公开访问分享内容（无需登录）
+func (h *Handler) GetSharedContent(c *gin.Context) {
+	token := c.Param("token")
+	if token == "" {
+		response.NotFound(c, service.ErrShareLinkNotFound.Error())
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	content, err := h.shareService.GetSharedContent(c.Request.Context(), token, c.GetHeader("X-Share-Password"), c.Query("cursor"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrShareLinkNotFound):
+			response.NotFound(c, err.Error())
+		case errors.Is(err, service.ErrSharePasswordRequired), errors.Is(err, service.ErrSharePasswordInvalid):
+			response.Unauthorized(c, err.Error())
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			response.BadRequest(c, err.Error())
+		default:
+			response.InternalServerError(c, "获取分享内容失败")
+		}
+		return
+	}
+
+	response.Success(c, content)
+}
@@ -180,6 +180,8 @@ func (h *Handler) GetSpaceMembers(c *gin.Context) {
 	type MemberResponse struct {
 		UserID   string `json:"user_id"`
 		Username string `json:"username"`
+		Nickname string `json:"nickname,omitempty"`
+		Color    string `json:"color,omitempty"`
 		Email    string `json:"email"`
 		Avatar   string `json:"avatar,omitempty"`
 		Role     string `json:"role"`
@@ -188,11 +190,19 @@ func (h *Handler) GetSpaceMembers(c *gin.Context) {
 
 	var memberResponses []MemberResponse
 	for _, member := range members {
+		// 优先使用成员在空间内设置的头像
+		avatar := member.User.AvatarURL
+		if member.AvatarURL != "" {
+			avatar = member.AvatarURL
+		}
+
 		memberResponses = append(memberResponses, MemberResponse{
 			UserID:   member.UserID.String(),
 			Username: member.User.Username,
+			Nickname: member.Nickname,
+			Color:    member.Color,
 			Email:    member.User.Email,
-			Avatar:   member.User.AvatarURL,
+			Avatar:   avatar,
 			Role:     string(member.Role),
 			JoinedAt: member.JoinedAt.Format("2006-01-02T15:04:05Z07:00"),
 		})
@@ -201,6 +211,35 @@ func (h *Handler) GetSpaceMembers(c *gin.Context) {
 	response.Success(c, memberResponses)
 }
 
+// UpdateMyProfile 修改自己在空间内的资料
+func (h *Handler) UpdateMyProfile(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	var req service.UpdateMemberProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误")
+		return
+	}
+
+	member, err := h.spaceService.UpdateMemberProfile(spaceID, userID, &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, member)
+}
+
 // DeleteSpace 删除空间
 func (h *Handler) DeleteSpace(c *gin.Context) {
 	userID, ok := middleware.GetCurrentUserID(c)
@@ -220,5 +259,91 @@ func (h *Handler) DeleteSpace(c *gin.Context) {
 		return
 	}
 
-	response.SuccessWithMessage(c, "空间删除成功", nil)
+	response.SuccessWithMessage(c, "空间已移入回收站", nil)
+}
+
+// ArchiveSpace 归档空间
+func (h *Handler) ArchiveSpace(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	space, err := h.spaceService.ArchiveSpace(spaceID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "空间已归档", space)
+}
+
+// UnarchiveSpace 取消归档
+func (h *Handler) UnarchiveSpace(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	space, err := h.spaceService.UnarchiveSpace(spaceID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "空间已取消归档", space)
+}
+
+// GetTrashedSpaces 获取回收站中的空间
+func (h *Handler) GetTrashedSpaces(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaces, err := h.spaceService.GetTrashedSpaces(userID)
+	if err != nil {
+		response.InternalServerError(c, "获取回收站失败")
+		return
+	}
+
+	response.Success(c, spaces)
+}
+
+// RestoreSpace 从回收站恢复空间
+func (h *Handler) RestoreSpace(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	space, err := h.spaceService.RestoreSpace(spaceID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "空间已恢复", space)
 }
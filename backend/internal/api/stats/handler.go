@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	statsService *service.StatsService
+}
+
+func NewHandler(statsService *service.StatsService) *Handler {
+	return &Handler{statsService: statsService}
+}
+
+// GetSpaceStats 获取空间统计数据
+func (h *Handler) GetSpaceStats(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	spaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "无效的空间ID")
+		return
+	}
+
+	stats, err := h.statsService.GetSpaceStats(c.Request.Context(), spaceID, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, stats)
+}
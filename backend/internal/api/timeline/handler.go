@@ -0,0 +1,121 @@
+package timeline
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/middleware"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/response"
+	"github.com/qq1477959747/linetime/backend/internal/service"
+)
+
+type Handler struct {
+	timelineService *service.TimelineService
+}
+
+func NewHandler(timelineService *service.TimelineService) *Handler {
+	return &Handler{timelineService: timelineService}
+}
+
+// GetTimeline 获取跨空间的个人时间线
+func (h *Handler) GetTimeline(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	req := service.TimelineRequest{
+		Tags:   splitQuery(c.Query("tags")),
+		Query:  c.Query("q"),
+		Cursor: c.Query("cursor"),
+		Limit:  limit,
+	}
+
+	// 解析查询参数
+	for _, id := range splitQuery(c.Query("space_ids")) {
+		spaceID, err := uuid.Parse(id)
+		if err != nil {
+			response.BadRequest(c, "无效的空间ID")
+			return
+		}
+		req.SpaceIDs = append(req.SpaceIDs, spaceID)
+	}
+
+	if authorStr := c.Query("author_id"); authorStr != "" {
+		authorID, err := uuid.Parse(authorStr)
+		if err != nil {
+			response.BadRequest(c, "无效的作者ID")
+			return
+		}
+		req.AuthorID = &authorID
+	}
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			response.BadRequest(c, "开始日期格式错误")
+			return
+		}
+		req.StartDate = &startDate
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			response.BadRequest(c, "结束日期格式错误")
+			return
+		}
+		req.EndDate = &endDate
+	}
+
+	events, err := h.timelineService.GetTimeline(&req, userID)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, events)
+}
+
+// GetOnThisDay 获取“那年今日”，date 为空时使用用户时区的当天
+func (h *Handler) GetOnThisDay(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			response.BadRequest(c, "日期格式错误")
+			return
+		}
+		date = &parsed
+	}
+
+	memories, err := h.timelineService.GetOnThisDay(userID, date)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, memories)
+}
+
+// splitQuery 拆分逗号分隔的查询参数，忽略空项
+func splitQuery(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
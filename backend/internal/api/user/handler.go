@@ -74,3 +74,55 @@ func (h *Handler) ClearDefaultSpace(c *gin.Context) {
 
 	response.Success(c, nil)
 }
+
+type SetDigestRequest struct {
+	OnThisDay *bool `json:"on_this_day" binding:"required"`
+}
+
+// SetDigest handles PUT /api/users/me/digest
+func (h *Handler) SetDigest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未授权")
+		return
+	}
+
+	var req SetDigestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "请求参数错误")
+		return
+	}
+
+	if err := h.userService.SetOnThisDayDigest(userID.(uuid.UUID), *req.OnThisDay); err != nil {
+		response.Error(c, http.StatusInternalServerError, "更新邮件订阅失败")
+		return
+	}
+
+	response.Success(c, gin.H{"on_this_day_digest": *req.OnThisDay})
+}
+
+type SetTimeZoneRequest struct {
+	TimeZone string `json:"time_zone" binding:"required"`
+}
+
+// SetTimeZone handles PUT /api/users/me/time-zone
+func (h *Handler) SetTimeZone(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未授权")
+		return
+	}
+
+	var req SetTimeZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "请求参数错误")
+		return
+	}
+
+	if err := h.userService.SetTimeZone(userID.(uuid.UUID), req.TimeZone); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"time_zone": req.TimeZone})
+}
@@ -48,12 +48,40 @@ func AutoMigrate() error {
 		&model.SpaceMember{},
 		&model.Event{},
 		&model.EventImage{},
+		&model.ShareLink{},
+		&model.SpaceActivity{},
+		&model.SpaceTag{},
+		&model.EventRevision{},
+		&model.EventComment{},
+		&model.EventReaction{},
+		&model.Mention{},
+		&model.Anniversary{},
 	)
 
 	if err != nil {
 		return err
 	}
 
+	if err := setupEventSearch(); err != nil {
+		return fmt.Errorf("初始化全文检索失败: %w", err)
+	}
+
+	if err := migrateLegacyEventImages(); err != nil {
+		return fmt.Errorf("迁移事件图片失败: %w", err)
+	}
+
+	if err := setupTagIndexes(); err != nil {
+		return fmt.Errorf("初始化标签索引失败: %w", err)
+	}
+
+	if err := setupOnThisDayIndex(); err != nil {
+		return fmt.Errorf("初始化那年今日索引失败: %w", err)
+	}
+
+	if err := backfillEventStartsAt(); err != nil {
+		return fmt.Errorf("计算事件开始时刻失败: %w", err)
+	}
+
 	log.Println("数据库迁移完成")
 	return nil
 }
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/storage"
+	"gorm.io/gorm"
+)
+
+// migrateLegacyEventImages 将 events.image_urls 中的旧图片列表迁移到 event_images
+// 迁移后清空 image_urls，重复执行时只处理尚未迁移的事件
+func migrateLegacyEventImages() error {
+	if !DB.Migrator().HasColumn("events", "image_urls") {
+		return nil
+	}
+
+	type legacyRow struct {
+		ID        uuid.UUID
+		ImageURLs string
+	}
+	var rows []legacyRow
+	err := DB.Raw(`
+		SELECT id, image_urls::text AS image_urls
+		FROM events
+		WHERE jsonb_typeof(image_urls) = 'array' AND jsonb_array_length(image_urls) > 0`).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	log.Printf("迁移 %d 个事件的图片到 event_images...", len(rows))
+	for _, row := range rows {
+		var urls []string
+		if err := json.Unmarshal([]byte(row.ImageURLs), &urls); err != nil {
+			log.Printf("解析事件 %s 的图片列表失败: %v", row.ID, err)
+			continue
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Unscoped().Model(&model.EventImage{}).Where("event_id = ?", row.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			// 已有图片记录的事件以 event_images 为准
+			if count == 0 {
+				images := make([]model.EventImage, 0, len(urls))
+				for i, url := range urls {
+					if url == "" {
+						continue
+					}
+					images = append(images, model.EventImage{
+						EventID:      row.ID,
+						ImageURL:     url,
+						ThumbnailURL: storage.ThumbnailURLFor(url),
+						SortOrder:    i,
+					})
+				}
+				if len(images) > 0 {
+					if err := tx.Omit("Event").Create(&images).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return tx.Exec(`UPDATE events SET image_urls = NULL WHERE id = ?`, row.ID).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+package database
+
+// setupOnThisDayIndex 为“那年今日”的按月、日查询创建表达式索引
+func setupOnThisDayIndex() error {
+	return DB.Exec(`CREATE INDEX IF NOT EXISTS idx_events_month_day ON events ((EXTRACT(MONTH FROM event_date)), (EXTRACT(DAY FROM event_date)))`).Error
+}
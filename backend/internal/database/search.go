@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/qq1477959747/linetime/backend/config"
+)
+
+var tsConfigRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+// setupEventSearch 创建事件全文检索所需的列、触发器和索引
+// search_vector 用于全文检索，search_text 用于中文等无法分词文本的 pg_trgm 模糊匹配
+func setupEventSearch() error {
+	tsConfig := config.AppConfig.Search.TSConfig
+	if !tsConfigRegex.MatchString(tsConfig) {
+		return fmt.Errorf("无效的全文检索配置: %s", tsConfig)
+	}
+
+	statements := []string{
+		`ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+		`ALTER TABLE events ADD COLUMN IF NOT EXISTS search_text text`,
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION events_search_update() RETURNS trigger AS $$
+DECLARE
+	tag_text text;
+BEGIN
+	SELECT COALESCE(string_agg(tag, ' '), '') INTO tag_text
+	FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(NEW.tags) = 'array' THEN NEW.tags ELSE '[]'::jsonb END) AS tag;
+
+	NEW.search_vector :=
+		setweight(to_tsvector('%[1]s', COALESCE(NEW.title, '')), 'A') ||
+		setweight(to_tsvector('%[1]s', tag_text), 'B') ||
+		setweight(to_tsvector('%[1]s', COALESCE(NEW.location, '')), 'B') ||
+		setweight(to_tsvector('%[1]s', COALESCE(NEW.description, '')), 'C') ||
+		setweight(to_tsvector('%[1]s', COALESCE(NEW.content, '')), 'D');
+	NEW.search_text := lower(concat_ws(' ', NEW.title, tag_text, NEW.location, NEW.description, NEW.content));
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql`, tsConfig),
+		`DROP TRIGGER IF EXISTS events_search_trigger ON events`,
+		`CREATE TRIGGER events_search_trigger BEFORE INSERT OR UPDATE OF title, content, description, location, tags
+			ON events FOR EACH ROW EXECUTE FUNCTION events_search_update()`,
+		`CREATE INDEX IF NOT EXISTS idx_events_search_vector ON events USING GIN (search_vector)`,
+		// 回填历史数据（触发器会重新计算检索列）
+		`UPDATE events SET title = title WHERE search_vector IS NULL`,
+	}
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	if config.AppConfig.Search.CJKTrigram {
+		// pg_trgm 需要数据库权限，失败时只记录日志，搜索会退化为无索引的 LIKE 匹配，按关键词位置排序
+		if err := DB.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).Error; err != nil {
+			log.Printf("启用 pg_trgm 扩展失败: %v", err)
+			return nil
+		}
+		if err := DB.Exec(`CREATE INDEX IF NOT EXISTS idx_events_search_text ON events USING GIN (search_text gin_trgm_ops)`).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
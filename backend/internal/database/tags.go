@@ -0,0 +1,16 @@
+package database
+
+// setupTagIndexes 创建标签相关的表达式索引
+// 标签名在空间内忽略大小写唯一；事件 tags 使用 GIN 索引支持 @> 包含查询
+func setupTagIndexes() error {
+	statements := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_space_tags_space_name ON space_tags (space_id, lower(name))`,
+		`CREATE INDEX IF NOT EXISTS idx_events_tags ON events USING GIN (tags jsonb_path_ops)`,
+	}
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
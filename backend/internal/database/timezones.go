@@ -0,0 +1,9 @@
+package database
+
+// backfillEventStartsAt 为引入时区之前的事件计算开始时刻，旧事件按 UTC 处理
+func backfillEventStartsAt() error {
+	return DB.Exec(`
+		UPDATE events
+		SET starts_at = (event_date + COALESCE(event_time, '00:00:00'::time)) AT TIME ZONE time_zone
+		WHERE starts_at IS NULL`).Error
+}
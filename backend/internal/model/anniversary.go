@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/anniversary"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
+	"gorm.io/gorm"
+)
+
+// Anniversary 空间内的纪念日，如在一起的日子、生日，可按年或按月重复
+type Anniversary struct {
+	ID               uuid.UUID              `gorm:"type:uuid;primary_key;" json:"id"`
+	SpaceID          uuid.UUID              `gorm:"type:uuid;not null;index" json:"space_id"`
+	CreatedBy        uuid.UUID              `gorm:"type:uuid;not null" json:"created_by"`
+	Title            string                 `gorm:"type:varchar(100);not null" json:"title"`
+	Date             time.Time              `gorm:"type:date;not null" json:"date"`
+	Recurrence       anniversary.Recurrence `gorm:"type:varchar(20);not null;default:'yearly'" json:"recurrence"`
+	RemindDaysBefore *int                   `json:"remind_days_before"` // 提前几天发送提醒邮件，为空表示不提醒
+	RemindedFor      *time.Time             `gorm:"type:date" json:"-"` // 已发送过提醒的那一次纪念日
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+
+	// 计算字段，不存储
+	Countdown *anniversary.Countdown `gorm:"-" json:"countdown,omitempty"`
+}
+
+func (a *Anniversary) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// MarshalJSON 纪念日日期输出为 YYYY-MM-DD，与请求中的格式一致
+func (a Anniversary) MarshalJSON() ([]byte, error) {
+	type alias Anniversary
+	return json.Marshal(&struct {
+		*alias
+		Date string `json:"date"`
+	}{
+		alias: (*alias)(&a),
+		Date:  a.Date.Format(localtime.DateLayout),
+	})
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnniversaryJSONDate(t *testing.T) {
+	item := Anniversary{Title: "在一起", Date: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)}
+	data, err := json.Marshal(item)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"date":"2024-05-20"`) {
+		t.Errorf("Marshal = %s, want date 2024-05-20", data)
+	}
+	if !strings.Contains(string(data), `"title":"在一起"`) {
+		t.Errorf("Marshal = %s, want other fields kept", data)
+	}
+}
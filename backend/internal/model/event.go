@@ -1,32 +1,76 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
 	"gorm.io/gorm"
 )
 
+// EventStatus 事件的发布状态
+type EventStatus string
+
+const (
+	EventStatusDraft     EventStatus = "draft"     // 草稿，仅作者可见
+	EventStatusPublished EventStatus = "published" // 已发布，空间成员可见
+	EventStatusScheduled EventStatus = "scheduled" // 定时发布（时间胶囊），到 PublishAt 后空间成员可见
+)
+
+// IsValid 检查发布状态是否受支持
+func (s EventStatus) IsValid() bool {
+	return s == EventStatusDraft || s == EventStatusPublished || s == EventStatusScheduled
+}
+
+// EventVisibility 事件在空间内的可见范围
+type EventVisibility string
+
+const (
+	EventVisibilityEveryone EventVisibility = "everyone" // 空间全体成员可见
+	EventVisibilityOnlyMe   EventVisibility = "only_me"  // 仅作者可见
+	EventVisibilityMembers  EventVisibility = "members"  // 作者和 VisibleTo 中的成员可见
+)
+
+// IsValid 检查可见范围是否受支持
+func (v EventVisibility) IsValid() bool {
+	return v == EventVisibilityEveryone || v == EventVisibilityOnlyMe || v == EventVisibilityMembers
+}
+
 type Event struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primary_key;" json:"id"`
-	SpaceID     uuid.UUID      `gorm:"type:uuid;not null;index:idx_space_date,priority:1" json:"space_id"`
-	UserID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
-	EventDate   time.Time      `gorm:"type:date;not null;index:idx_space_date,priority:2" json:"event_date"`
-	EventTime   *time.Time     `gorm:"type:time" json:"event_time"`
-	Title       string         `gorm:"type:varchar(200)" json:"title"`
-	Content     string         `gorm:"type:text" json:"content"`
-	Description string         `gorm:"type:text" json:"description"`
-	Location    string         `gorm:"type:varchar(200)" json:"location"`
-	Tags        []string       `gorm:"type:jsonb;serializer:json" json:"tags"`
-	ImageURLs   []string       `gorm:"type:jsonb;serializer:json" json:"images"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
+	SpaceID     uuid.UUID       `gorm:"type:uuid;not null;index:idx_space_date,priority:1;index:idx_space_end_date,priority:1" json:"space_id"`
+	UserID      uuid.UUID       `gorm:"type:uuid;not null;index" json:"user_id"`
+	EventDate   time.Time       `gorm:"type:date;not null;index:idx_space_date,priority:2" json:"event_date"`
+	EventTime   *time.Time      `gorm:"type:time" json:"event_time"`
+	EndDate     *time.Time      `gorm:"type:date;index:idx_space_end_date,priority:2" json:"end_date"` // 多天事件的结束日期，为空表示当天事件
+	EndTime     *time.Time      `gorm:"type:time" json:"end_time"`
+	TimeZone    string          `gorm:"type:varchar(64);not null;default:'UTC'" json:"time_zone"` // 日期和时间所在的 IANA 时区
+	StartsAt    time.Time       `gorm:"index" json:"starts_at"`                                   // 开始时刻（UTC），由本地日期、时间和时区换算，用于跨时区排序
+	Title       string          `gorm:"type:varchar(200)" json:"title"`
+	Content     string          `gorm:"type:text" json:"content"`
+	Description string          `gorm:"type:text" json:"description"`
+	Location    string          `gorm:"type:varchar(200)" json:"location"`
+	Latitude    *float64        `gorm:"type:double precision;index:idx_event_geo,priority:1" json:"latitude"`
+	Longitude   *float64        `gorm:"type:double precision;index:idx_event_geo,priority:2" json:"longitude"`
+	PlaceName   string          `gorm:"type:varchar(200)" json:"place_name"`
+	Tags        []string        `gorm:"type:jsonb;serializer:json" json:"tags"`
+	ImageURLs   []string        `gorm:"-" json:"images"`                   // 由 Images 按排序生成，兼容只读取 URL 列表的客户端
+	Version     int             `gorm:"not null;default:1" json:"version"` // 乐观锁版本号，每次修改加一
+	Status      EventStatus     `gorm:"type:varchar(20);not null;default:'published';index" json:"status"`
+	PublishAt   *time.Time      `gorm:"index" json:"publish_at"` // 定时发布时间，仅 scheduled 状态使用
+	Visibility  EventVisibility `gorm:"type:varchar(20);not null;default:'everyone'" json:"visibility"`
+	VisibleTo   []uuid.UUID     `gorm:"type:jsonb;serializer:json" json:"visible_to"` // 可见成员，仅 members 范围使用
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"-"`
 
 	// 关联
 	Space  Space        `gorm:"foreignKey:SpaceID" json:"space,omitempty"`
 	User   User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Images []EventImage `gorm:"foreignKey:EventID" json:"-"`
+	Images []EventImage `gorm:"foreignKey:EventID" json:"photos"`
+
+	Reactions []ReactionSummary `gorm:"-" json:"reactions"` // 表情回应汇总，由仓库层按当前用户填充
 }
 
 func (e *Event) BeforeCreate(tx *gorm.DB) error {
@@ -35,3 +79,113 @@ func (e *Event) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// BeforeSave 根据本地日期、时间和时区计算开始时刻
+func (e *Event) BeforeSave(tx *gorm.DB) error {
+	if e.TimeZone == "" {
+		e.TimeZone = localtime.DefaultZone
+	}
+	loc, err := localtime.LoadZone(e.TimeZone)
+	if err != nil {
+		return err
+	}
+	e.StartsAt = localtime.Instant(e.EventDate, e.EventTime, loc)
+	return nil
+}
+
+// MarshalJSON 日期和时间按事件时区的本地值输出为 YYYY-MM-DD 和 HH:MM:SS，
+// 避免客户端按自己的时区换算后日期错位
+func (e Event) MarshalJSON() ([]byte, error) {
+	type alias Event
+	return json.Marshal(&struct {
+		*alias
+		EventDate string  `json:"event_date"`
+		EventTime *string `json:"event_time"`
+		EndDate   *string `json:"end_date"`
+		EndTime   *string `json:"end_time"`
+	}{
+		alias:     (*alias)(&e),
+		EventDate: e.EventDate.Format(localtime.DateLayout),
+		EventTime: localtime.FormatClock(e.EventTime),
+		EndDate:   localtime.FormatDate(e.EndDate),
+		EndTime:   localtime.FormatClock(e.EndTime),
+	})
+}
+
+// MarshalEventWith 将 extra 的字段合并到事件的 JSON 对象中。内嵌 Event 的响应结构会继承
+// Event.MarshalJSON 而丢失自己的字段，需要通过它实现 MarshalJSON
+func MarshalEventWith(e *Event, extra interface{}) ([]byte, error) {
+	event, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) <= 2 {
+		return event, nil
+	}
+	merged := make([]byte, 0, len(event)+len(fields))
+	merged = append(merged, event[:len(event)-1]...)
+	merged = append(merged, ',')
+	return append(merged, fields[1:]...), nil
+}
+
+// AfterFind 根据图片记录生成 URL 列表
+func (e *Event) AfterFind(tx *gorm.DB) error {
+	e.ImageURLs = make([]string, 0, len(e.Images))
+	for _, image := range e.Images {
+		e.ImageURLs = append(e.ImageURLs, image.ImageURL)
+	}
+	return nil
+}
+
+// LastDate 事件覆盖的最后一天，单日事件即 EventDate
+func (e *Event) LastDate() time.Time {
+	if e.EndDate != nil {
+		return *e.EndDate
+	}
+	return e.EventDate
+}
+
+// IsPublished 事件是否已对空间成员公开：已发布，或定时发布的时间已到
+func (e *Event) IsPublished(now time.Time) bool {
+	switch e.Status {
+	case EventStatusPublished, "":
+		return true
+	case EventStatusScheduled:
+		return e.PublishAt != nil && !now.Before(*e.PublishAt)
+	default:
+		return false
+	}
+}
+
+// IsPublic 事件是否对空间全体成员公开，统计、动态和公开分享只使用公开的事件
+func (e *Event) IsPublic(now time.Time) bool {
+	return e.IsPublished(now) && (e.Visibility == EventVisibilityEveryone || e.Visibility == "")
+}
+
+// IsVisibleTo 作者总能看到自己的事件，其他成员只能看到已公开且在可见范围内的事件
+func (e *Event) IsVisibleTo(userID uuid.UUID, now time.Time) bool {
+	if e.UserID == userID {
+		return true
+	}
+	if !e.IsPublished(now) {
+		return false
+	}
+
+	switch e.Visibility {
+	case EventVisibilityEveryone, "":
+		return true
+	case EventVisibilityMembers:
+		for _, id := range e.VisibleTo {
+			if id == userID {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventComment 事件评论，RootID 为空表示顶层评论，回复的 RootID 指向所在楼层的顶层评论
+type EventComment struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID   uuid.UUID      `gorm:"type:uuid;not null;index:idx_event_comment,priority:1" json:"event_id"`
+	UserID    uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
+	ParentID  *uuid.UUID     `gorm:"type:uuid" json:"parent_id"`
+	RootID    *uuid.UUID     `gorm:"type:uuid;index" json:"root_id"`
+	Content   string         `gorm:"type:text;not null" json:"content"`
+	EditedAt  *time.Time     `json:"edited_at"`
+	CreatedAt time.Time      `gorm:"index:idx_event_comment,priority:2" json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联
+	User    User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Replies []EventComment `gorm:"-" json:"replies,omitempty"`
+}
+
+func (ec *EventComment) BeforeCreate(tx *gorm.DB) error {
+	if ec.ID == uuid.Nil {
+		ec.ID = uuid.New()
+	}
+	return nil
+}
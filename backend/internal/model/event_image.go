@@ -12,12 +12,14 @@ type EventImage struct {
 	EventID      uuid.UUID      `gorm:"type:uuid;not null;index:idx_event_sort,priority:1" json:"event_id"`
 	ImageURL     string         `gorm:"type:text;not null" json:"image_url"`
 	ThumbnailURL string         `gorm:"type:text;not null" json:"thumbnail_url"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
 	SortOrder    int            `gorm:"not null;index:idx_event_sort,priority:2" json:"sort_order"`
 	UploadedAt   time.Time      `json:"uploaded_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联
-	Event Event `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Event Event `gorm:"foreignKey:EventID" json:"-"`
 }
 
 func (ei *EventImage) BeforeCreate(tx *gorm.DB) error {
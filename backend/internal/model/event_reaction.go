@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventReaction 事件的表情回应，同一用户对同一事件的同一表情只记录一次
+type EventReaction struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_event_reaction,priority:1" json:"event_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_event_reaction,priority:2" json:"user_id"`
+	Emoji     string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_event_reaction,priority:3" json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (er *EventReaction) BeforeCreate(tx *gorm.DB) error {
+	if er.ID == uuid.Nil {
+		er.ID = uuid.New()
+	}
+	return nil
+}
+
+// ReactionSummary 某个表情在事件上的汇总
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
+	"gorm.io/gorm"
+)
+
+// EventSnapshot 事件可编辑内容的快照
+type EventSnapshot struct {
+	EventDate   time.Time  `json:"event_date"`
+	EventTime   *time.Time `json:"event_time"`
+	EndDate     *time.Time `json:"end_date"`
+	EndTime     *time.Time `json:"end_time"`
+	TimeZone    string     `json:"time_zone"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Description string     `json:"description"`
+	Location    string     `json:"location"`
+	Latitude    *float64   `json:"latitude"`
+	Longitude   *float64   `json:"longitude"`
+	PlaceName   string     `json:"place_name"`
+	Tags        []string   `json:"tags"`
+	Images      []string   `json:"images"`
+}
+
+// FieldChange 单个字段的修改前后值
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// EventRevision 事件的一次修改记录，Snapshot 为修改前的内容
+type EventRevision struct {
+	ID        uuid.UUID     `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID   uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_event_revision,priority:1" json:"event_id"`
+	Revision  int           `gorm:"not null;uniqueIndex:idx_event_revision,priority:2" json:"revision"`
+	EditorID  uuid.UUID     `gorm:"type:uuid;not null" json:"editor_id"`
+	Changes   []FieldChange `gorm:"type:jsonb;serializer:json" json:"changes"`
+	Snapshot  EventSnapshot `gorm:"type:jsonb;serializer:json" json:"snapshot"`
+	CreatedAt time.Time     `gorm:"index" json:"created_at"`
+
+	// 关联
+	Editor User `gorm:"foreignKey:EditorID" json:"editor,omitempty"`
+}
+
+func (er *EventRevision) BeforeCreate(tx *gorm.DB) error {
+	if er.ID == uuid.Nil {
+		er.ID = uuid.New()
+	}
+	return nil
+}
+
+// Snapshot 生成事件当前内容的快照
+func (e *Event) Snapshot() EventSnapshot {
+	return EventSnapshot{
+		EventDate:   e.EventDate,
+		EventTime:   e.EventTime,
+		EndDate:     e.EndDate,
+		EndTime:     e.EndTime,
+		TimeZone:    e.TimeZone,
+		Title:       e.Title,
+		Content:     e.Content,
+		Description: e.Description,
+		Location:    e.Location,
+		Latitude:    e.Latitude,
+		Longitude:   e.Longitude,
+		PlaceName:   e.PlaceName,
+		Tags:        e.Tags,
+		Images:      e.ImageURLs,
+	}
+}
+
+// ApplySnapshot 将快照中的文字与地点内容写回事件，图片由图片接口单独维护，不随快照恢复
+func (e *Event) ApplySnapshot(snapshot *EventSnapshot) {
+	e.EventDate = snapshot.EventDate
+	e.EventTime = snapshot.EventTime
+	e.EndDate = snapshot.EndDate
+	e.EndTime = snapshot.EndTime
+	// 时区字段出现之前写入的快照一律按 UTC 记录，与回填 starts_at 时的假设一致
+	e.TimeZone = snapshot.TimeZone
+	if e.TimeZone == "" {
+		e.TimeZone = localtime.DefaultZone
+	}
+	e.Title = snapshot.Title
+	e.Content = snapshot.Content
+	e.Description = snapshot.Description
+	e.Location = snapshot.Location
+	e.Latitude = snapshot.Latitude
+	e.Longitude = snapshot.Longitude
+	e.PlaceName = snapshot.PlaceName
+	e.Tags = snapshot.Tags
+}
+
+// DiffSnapshots 比较两个快照，返回有变化的字段
+func DiffSnapshots(before, after *EventSnapshot) []FieldChange {
+	var changes []FieldChange
+	add := func(field string, old, new interface{}) {
+		if !reflect.DeepEqual(old, new) {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	add("event_date", before.EventDate.Format(localtime.DateLayout), after.EventDate.Format(localtime.DateLayout))
+	add("event_time", localtime.FormatClock(before.EventTime), localtime.FormatClock(after.EventTime))
+	add("end_date", localtime.FormatDate(before.EndDate), localtime.FormatDate(after.EndDate))
+	add("end_time", localtime.FormatClock(before.EndTime), localtime.FormatClock(after.EndTime))
+	add("time_zone", before.TimeZone, after.TimeZone)
+	add("title", before.Title, after.Title)
+	add("content", before.Content, after.Content)
+	add("description", before.Description, after.Description)
+	add("location", before.Location, after.Location)
+	add("latitude", before.Latitude, after.Latitude)
+	add("longitude", before.Longitude, after.Longitude)
+	add("place_name", before.PlaceName, after.PlaceName)
+	add("tags", nonNilStrings(before.Tags), nonNilStrings(after.Tags))
+	add("images", nonNilStrings(before.Images), nonNilStrings(after.Images))
+	return changes
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
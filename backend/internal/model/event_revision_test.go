@@ -0,0 +1,72 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func testSnapshot() *EventSnapshot {
+	clock := time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC)
+	return &EventSnapshot{
+		EventDate: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+		EventTime: &clock,
+		TimeZone:  "Asia/Shanghai",
+		Title:     "出游",
+		Tags:      []string{"旅行"},
+	}
+}
+
+func TestDiffSnapshotsUnchanged(t *testing.T) {
+	before, after := testSnapshot(), testSnapshot()
+	after.Images = nil
+	before.Images = []string{}
+	if changes := DiffSnapshots(before, after); len(changes) != 0 {
+		t.Errorf("DiffSnapshots() = %v, want no changes", changes)
+	}
+}
+
+func TestDiffSnapshotsTimeZoneOnly(t *testing.T) {
+	before, after := testSnapshot(), testSnapshot()
+	after.TimeZone = "America/New_York"
+
+	changes := DiffSnapshots(before, after)
+	if len(changes) != 1 {
+		t.Fatalf("DiffSnapshots() = %v, want exactly one change", changes)
+	}
+	if changes[0].Field != "time_zone" || changes[0].Old != "Asia/Shanghai" || changes[0].New != "America/New_York" {
+		t.Errorf("DiffSnapshots() = %+v, want time_zone Asia/Shanghai -> America/New_York", changes[0])
+	}
+}
+
+func TestApplySnapshotLegacyZone(t *testing.T) {
+	snapshot := testSnapshot()
+	snapshot.TimeZone = ""
+	event := &Event{TimeZone: "Asia/Shanghai"}
+
+	event.ApplySnapshot(snapshot)
+	if event.TimeZone != "UTC" {
+		t.Errorf("ApplySnapshot() TimeZone = %q, want UTC for a snapshot without a zone", event.TimeZone)
+	}
+
+	snapshot.TimeZone = "America/New_York"
+	event.ApplySnapshot(snapshot)
+	if event.TimeZone != "America/New_York" {
+		t.Errorf("ApplySnapshot() TimeZone = %q, want America/New_York", event.TimeZone)
+	}
+}
+
+func TestDiffSnapshotsClearedTime(t *testing.T) {
+	before, after := testSnapshot(), testSnapshot()
+	after.EventTime = nil
+
+	changes := DiffSnapshots(before, after)
+	if len(changes) != 1 || changes[0].Field != "event_time" {
+		t.Fatalf("DiffSnapshots() = %v, want one event_time change", changes)
+	}
+	if old, ok := changes[0].Old.(*string); !ok || old == nil || *old != "09:30:00" {
+		t.Errorf("DiffSnapshots() old = %v, want 09:30:00", changes[0].Old)
+	}
+	if new, ok := changes[0].New.(*string); !ok || new != nil {
+		t.Errorf("DiffSnapshots() new = %v, want nil", changes[0].New)
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Mention 事件内容或评论中对空间成员的 @ 提及，同时作为被提及用户的站内通知
+type Mention struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_mention_user,priority:1" json:"user_id"` // 被提及的用户
+	ActorID   uuid.UUID  `gorm:"type:uuid;not null" json:"actor_id"`
+	SpaceID   uuid.UUID  `gorm:"type:uuid;not null" json:"space_id"`
+	EventID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"event_id"`
+	CommentID *uuid.UUID `gorm:"type:uuid;index" json:"comment_id"` // 为空表示在事件内容中提及
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"index:idx_mention_user,priority:2" json:"created_at"`
+
+	// 关联
+	Actor   User          `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Space   Space         `gorm:"foreignKey:SpaceID" json:"space,omitempty"`
+	Event   Event         `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Comment *EventComment `gorm:"foreignKey:CommentID" json:"comment,omitempty"`
+}
+
+func (m *Mention) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
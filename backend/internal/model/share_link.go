@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareScope string
+
+const (
+	ShareScopeSpace ShareScope = "space"
+	ShareScopeRange ShareScope = "range"
+	ShareScopeEvent ShareScope = "event"
+)
+
+// ShareLink 公开只读分享链接，无需登录即可访问
+type ShareLink struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	Token        string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"token"`
+	SpaceID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"space_id"`
+	CreatedBy    uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	Scope        ShareScope `gorm:"type:varchar(20);not null" json:"scope"`
+	EventID      *uuid.UUID `gorm:"type:uuid" json:"event_id,omitempty"`
+	StartDate    *time.Time `gorm:"type:date" json:"start_date,omitempty"`
+	EndDate      *time.Time `gorm:"type:date" json:"end_date,omitempty"`
+	PasswordHash string     `gorm:"type:varchar(255)" json:"-"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (sl *ShareLink) BeforeCreate(tx *gorm.DB) error {
+	if sl.ID == uuid.Nil {
+		sl.ID = uuid.New()
+	}
+	return nil
+}
+
+// HasPassword 是否设置了访问密码
+func (sl *ShareLink) HasPassword() bool {
+	return sl.PasswordHash != ""
+}
+
+// IsActive 未撤销且未过期
+func (sl *ShareLink) IsActive() bool {
+	if sl.RevokedAt != nil {
+		return false
+	}
+	return sl.ExpiresAt == nil || time.Now().Before(*sl.ExpiresAt)
+}
@@ -23,6 +23,7 @@ type Space struct {
 	InviteLink  string         `gorm:"type:text;not null" json:"invite_link"`
 	OwnerID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"owner_id"`
 	Type        SpaceType      `gorm:"type:varchar(20);not null" json:"type"`
+	ArchivedAt  *time.Time     `gorm:"index" json:"archived_at"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -38,3 +39,8 @@ func (s *Space) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// IsArchived 归档后的空间为只读
+func (s *Space) IsArchived() bool {
+	return s.ArchivedAt != nil
+}
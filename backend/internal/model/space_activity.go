@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ActivityType string
+
+const (
+	ActivityEventCreated    ActivityType = "event_created"
+	ActivityEventUpdated    ActivityType = "event_updated"
+	ActivityEventDeleted    ActivityType = "event_deleted"
+	ActivityEventRestored   ActivityType = "event_restored"
+	ActivityCommentAdded    ActivityType = "comment_added"
+	ActivityMemberJoined    ActivityType = "member_joined"
+	ActivityMemberRemoved   ActivityType = "member_removed"
+	ActivityInviteRefreshed ActivityType = "invite_refreshed"
+)
+
+// SpaceActivity 空间动态记录
+type SpaceActivity struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primary_key;" json:"id"`
+	SpaceID   uuid.UUID    `gorm:"type:uuid;not null;index:idx_space_activity,priority:1" json:"space_id"`
+	ActorID   uuid.UUID    `gorm:"type:uuid;not null" json:"actor_id"`
+	Type      ActivityType `gorm:"type:varchar(30);not null" json:"type"`
+	TargetID  *uuid.UUID   `gorm:"type:uuid" json:"target_id"`
+	Summary   string       `gorm:"type:varchar(200)" json:"summary"`
+	CreatedAt time.Time    `gorm:"index:idx_space_activity,priority:2" json:"created_at"`
+
+	// 关联
+	Actor User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+func (a *SpaceActivity) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -15,11 +15,20 @@ const (
 )
 
 type SpaceMember struct {
-	ID       uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
-	SpaceID  uuid.UUID  `gorm:"type:uuid;not null;index:idx_space_user,priority:1" json:"space_id"`
-	UserID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_space_user,priority:2;index:idx_user" json:"user_id"`
-	Role     MemberRole `gorm:"type:varchar(20);not null" json:"role"`
-	JoinedAt time.Time  `json:"joined_at"`
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	SpaceID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_space_user,priority:1" json:"space_id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_space_user,priority:2;index:idx_user" json:"user_id"`
+	Role       MemberRole `gorm:"type:varchar(20);not null" json:"role"`
+	JoinedAt   time.Time  `json:"joined_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"` // 最后一次查看空间动态的时间
+
+	// 空间移入回收站前是否为该成员的默认空间，恢复空间时据此还原
+	WasDefault bool `gorm:"not null;default:false" json:"-"`
+
+	// 成员在该空间内的个人资料
+	Nickname  string `gorm:"type:varchar(50)" json:"nickname"`
+	Color     string `gorm:"type:varchar(20)" json:"color"`
+	AvatarURL string `gorm:"type:text" json:"avatar_url"`
 
 	// 关联
 	Space Space `gorm:"foreignKey:SpaceID" json:"space,omitempty"`
@@ -35,3 +44,22 @@ func (sm *SpaceMember) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// MemberProfile 成员在某个空间内的显示身份
+type MemberProfile struct {
+	Nickname  string `json:"nickname,omitempty"`
+	Color     string `json:"color,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// Profile 返回成员的空间内资料，未设置任何字段时返回 nil
+func (sm *SpaceMember) Profile() *MemberProfile {
+	if sm.Nickname == "" && sm.Color == "" && sm.AvatarURL == "" {
+		return nil
+	}
+	return &MemberProfile{
+		Nickname:  sm.Nickname,
+		Color:     sm.Color,
+		AvatarURL: sm.AvatarURL,
+	}
+}
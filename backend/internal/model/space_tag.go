@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SpaceTag 空间内的标签登记，事件中的标签统一使用 Name，Aliases 中的写法会被归并到 Name
+type SpaceTag struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	SpaceID   uuid.UUID `gorm:"type:uuid;not null;index" json:"space_id"`
+	Name      string    `gorm:"type:varchar(50);not null" json:"name"`
+	Color     string    `gorm:"type:varchar(20)" json:"color"`
+	Aliases   []string  `gorm:"type:jsonb;serializer:json" json:"aliases"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 统计字段，不存储
+	UsageCount int64 `gorm:"-" json:"usage_count"`
+}
+
+func (st *SpaceTag) BeforeCreate(tx *gorm.DB) error {
+	if st.ID == uuid.Nil {
+		st.ID = uuid.New()
+	}
+	return nil
+}
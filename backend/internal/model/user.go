@@ -8,17 +8,23 @@ import (
 )
 
 type User struct {
-	ID             uuid.UUID      `gorm:"type:uuid;primary_key;" json:"id"`
-	Email          string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Username       string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
-	PasswordHash   string         `gorm:"type:varchar(255)" json:"-"`
-	AvatarURL      string         `gorm:"type:text" json:"avatar_url"`
-	DefaultSpaceID *uuid.UUID     `gorm:"type:uuid;index" json:"default_space_id"`
-	GoogleID       *string        `gorm:"type:varchar(255);uniqueIndex" json:"-"`
-	AuthProvider   string         `gorm:"type:varchar(20);default:'local'" json:"auth_provider"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;" json:"id"`
+	Email           string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Username        string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
+	PasswordHash    string         `gorm:"type:varchar(255)" json:"-"`
+	AvatarURL       string         `gorm:"type:text" json:"avatar_url"`
+	DefaultSpaceID  *uuid.UUID     `gorm:"type:uuid;index" json:"default_space_id"`
+	GoogleID        *string        `gorm:"type:varchar(255);uniqueIndex" json:"-"`
+	AuthProvider    string         `gorm:"type:varchar(20);default:'local'" json:"auth_provider"`
+	OnThisDayDigest bool           `gorm:"not null;default:false" json:"on_this_day_digest"`         // 是否订阅“那年今日”每日邮件
+	DigestSentOn    *time.Time     `gorm:"type:date" json:"-"`                                       // 最近一次发送每日邮件的日期
+	TimeZone        string         `gorm:"type:varchar(64);not null;default:'UTC'" json:"time_zone"` // IANA 时区，新建事件默认使用
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 空间内的显示身份（昵称、颜色、头像），仅在按空间查询时填充
+	SpaceProfile *MemberProfile `gorm:"-" json:"space_profile,omitempty"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
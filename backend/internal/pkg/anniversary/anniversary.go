@@ -0,0 +1,117 @@
+package anniversary
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Recurrence 纪念日的重复方式
+type Recurrence string
+
+const (
+	None    Recurrence = "none"
+	Yearly  Recurrence = "yearly"
+	Monthly Recurrence = "monthly"
+)
+
+// IsValid 检查重复方式是否受支持
+func (r Recurrence) IsValid() bool {
+	return r == None || r == Yearly || r == Monthly
+}
+
+// Countdown 纪念日相对于某一天的计算结果
+type Countdown struct {
+	DayCount   int        `json:"day_count"`  // 从纪念日当天算起的第几天（当天为第 1 天），尚未到来时为 0
+	NextDate   *time.Time `json:"next_date"`  // 下一次（含今天）纪念日，不重复且已过去时为空
+	DaysUntil  *int       `json:"days_until"` // 距下一次纪念日的天数，今天即为 0
+	Occurrence int        `json:"occurrence"` // 下一次是第几个周年或第几个月，纪念日本身为 0
+}
+
+// MarshalJSON 下一次纪念日输出为 YYYY-MM-DD
+func (c Countdown) MarshalJSON() ([]byte, error) {
+	type alias Countdown
+	var next *string
+	if c.NextDate != nil {
+		s := c.NextDate.Format("2006-01-02")
+		next = &s
+	}
+	return json.Marshal(&struct {
+		alias
+		NextDate *string `json:"next_date"`
+	}{
+		alias:    alias(c),
+		NextDate: next,
+	})
+}
+
+// Compute 计算纪念日 date 在 today 这一天的“第 N 天”和倒计时，只比较日期部分。
+// 按月重复时，没有对应日期的月份取当月最后一天；2 月 29 日的年度纪念日在平年取 2 月 28 日。
+func Compute(date time.Time, recurrence Recurrence, today time.Time) Countdown {
+	date = truncate(date)
+	today = truncate(today)
+
+	var c Countdown
+	if !today.Before(date) {
+		c.DayCount = daysBetween(date, today) + 1
+	}
+
+	n := 0
+	switch recurrence {
+	case Yearly:
+		n = today.Year() - date.Year()
+	case Monthly:
+		n = (today.Year()-date.Year())*12 + int(today.Month()-date.Month())
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	next := occurrence(date, recurrence, n)
+	if next.Before(today) {
+		if recurrence == None || recurrence == "" {
+			return c
+		}
+		n++
+		next = occurrence(date, recurrence, n)
+	}
+
+	days := daysBetween(today, next)
+	c.NextDate = &next
+	c.DaysUntil = &days
+	c.Occurrence = n
+	return c
+}
+
+// occurrence 返回纪念日的第 n 次重复
+func occurrence(date time.Time, recurrence Recurrence, n int) time.Time {
+	switch recurrence {
+	case Yearly:
+		return addMonths(date, 12*n)
+	case Monthly:
+		return addMonths(date, n)
+	default:
+		return date
+	}
+}
+
+// addMonths 加上 n 个月，日期超出目标月份天数时取月末
+func addMonths(date time.Time, n int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	day := date.Day()
+	if last := daysIn(firstOfMonth.Year(), firstOfMonth.Month()); day > last {
+		day = last
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
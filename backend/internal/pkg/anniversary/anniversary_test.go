@@ -0,0 +1,90 @@
+package anniversary
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCompute(t *testing.T) {
+	cases := []struct {
+		name       string
+		date       string
+		recurrence Recurrence
+		today      string
+		dayCount   int
+		next       string
+		daysUntil  int
+		occurrence int
+	}{
+		{"same day is day 1", "2024-05-20", Yearly, "2024-05-20", 1, "2024-05-20", 0, 0},
+		{"yearly upcoming", "2020-05-20", Yearly, "2024-05-10", 1452, "2024-05-20", 10, 4},
+		{"yearly passed this year", "2020-05-20", Yearly, "2024-06-01", 1474, "2025-05-20", 353, 5},
+		{"yearly on anniversary", "2020-05-20", Yearly, "2024-05-20", 1462, "2024-05-20", 0, 4},
+		{"leap day in common year", "2020-02-29", Yearly, "2023-02-01", 1069, "2023-02-28", 27, 3},
+		{"monthly clamps to month end", "2024-01-31", Monthly, "2024-02-10", 11, "2024-02-29", 19, 1},
+		{"monthly next month", "2024-01-15", Monthly, "2024-03-20", 66, "2024-04-15", 26, 3},
+		{"future date", "2030-01-01", Yearly, "2029-12-25", 0, "2030-01-01", 7, 0},
+		{"one-off upcoming", "2024-12-25", None, "2024-12-01", 0, "2024-12-25", 24, 0},
+	}
+	for _, c := range cases {
+		got := Compute(day(c.date), c.recurrence, day(c.today))
+		if got.DayCount != c.dayCount {
+			t.Errorf("%s: DayCount = %d, want %d", c.name, got.DayCount, c.dayCount)
+		}
+		if got.NextDate == nil || !got.NextDate.Equal(day(c.next)) {
+			t.Errorf("%s: NextDate = %v, want %s", c.name, got.NextDate, c.next)
+			continue
+		}
+		if *got.DaysUntil != c.daysUntil {
+			t.Errorf("%s: DaysUntil = %d, want %d", c.name, *got.DaysUntil, c.daysUntil)
+		}
+		if got.Occurrence != c.occurrence {
+			t.Errorf("%s: Occurrence = %d, want %d", c.name, got.Occurrence, c.occurrence)
+		}
+	}
+}
+
+func TestComputeOneOffPassed(t *testing.T) {
+	got := Compute(day("2024-01-01"), None, day("2024-01-11"))
+	if got.DayCount != 11 || got.NextDate != nil || got.DaysUntil != nil {
+		t.Errorf("Compute one-off passed = %+v", got)
+	}
+}
+
+func TestComputeIgnoresTimeOfDay(t *testing.T) {
+	today := time.Date(2024, 5, 20, 23, 59, 0, 0, time.FixedZone("CST", 8*3600))
+	got := Compute(day("2024-05-19"), None, today)
+	if got.DayCount != 2 {
+		t.Errorf("DayCount = %d, want 2", got.DayCount)
+	}
+}
+
+func TestCountdownJSON(t *testing.T) {
+	c := Compute(day("2020-05-20"), Yearly, day("2024-05-10"))
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"next_date":"2024-05-20"`) || !strings.Contains(string(data), `"days_until":10`) {
+		t.Errorf("Marshal = %s, want next_date 2024-05-20 and days_until 10", data)
+	}
+
+	c = Compute(day("2020-05-20"), None, day("2024-05-10"))
+	data, err = json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"next_date":null`) {
+		t.Errorf("Marshal = %s, want null next_date", data)
+	}
+}
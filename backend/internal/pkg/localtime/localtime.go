@@ -0,0 +1,114 @@
+package localtime
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	DateLayout  = "2006-01-02"
+	ClockLayout = "15:04:05"
+
+	// DefaultZone 未设置时区的用户和历史事件使用的时区
+	DefaultZone = "UTC"
+)
+
+var (
+	ErrInvalidZone  = errors.New("无效的时区，请使用 IANA 时区名称，如 Asia/Shanghai")
+	ErrInvalidDate  = errors.New("日期格式应为 YYYY-MM-DD")
+	ErrInvalidClock = errors.New("时间格式应为 HH:MM 或 HH:MM:SS")
+)
+
+// LoadZone 按 IANA 名称加载时区，不接受依赖服务器环境的 Local
+func LoadZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidZone
+	}
+	return loc, nil
+}
+
+// ParseDate 解析本地日期 YYYY-MM-DD，返回 UTC 零点。
+// 兼容旧客户端提交的 RFC 3339 时间，只取字面上的日期部分，不做时区换算
+func ParseDate(s string) (time.Time, error) {
+	if len(s) > len(DateLayout) && s[len(DateLayout)] == 'T' {
+		s = s[:len(DateLayout)]
+	}
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return t, nil
+}
+
+// ParseClock 解析本地时间 HH:MM 或 HH:MM:SS，返回 0000-01-01 当天的 UTC 时间。
+// 兼容 RFC 3339 时间，只取字面上的时分秒
+func ParseClock(s string) (time.Time, error) {
+	if i := strings.IndexByte(s, 'T'); i >= 0 {
+		s = s[i+1:]
+		if end := strings.IndexAny(s, "Z+-."); end >= 0 {
+			s = s[:end]
+		}
+	}
+
+	layout := ClockLayout
+	if strings.Count(s, ":") == 1 {
+		layout = "15:04"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidClock
+	}
+	return t, nil
+}
+
+// FormatDate 将日期格式化为 YYYY-MM-DD，为空时返回 nil
+func FormatDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(DateLayout)
+	return &s
+}
+
+// FormatClock 将时间格式化为 HH:MM:SS，为空时返回 nil
+func FormatClock(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(ClockLayout)
+	return &s
+}
+
+// Instant 将时区 loc 中的本地日期和时间换算为绝对时间（UTC），没有时间时取当天零点，
+// 用于不同时区事件之间的排序
+func Instant(date time.Time, clock *time.Time, loc *time.Location) time.Time {
+	hour, min, sec := 0, 0, 0
+	if clock != nil {
+		hour, min, sec = clock.Clock()
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, min, sec, 0, loc).UTC()
+}
+
+// In 返回 now 在时区 name 中的本地时间，时区为空或无效时按 DefaultZone 处理
+func In(name string, now time.Time) time.Time {
+	loc, err := LoadZone(name)
+	if err != nil {
+		loc = time.UTC
+	}
+	return now.In(loc)
+}
+
+// DateOf 取本地时间 t 的日历日期，返回 UTC 零点，与 ParseDate 的结果一致
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Today 返回时区 name 中 now 所在的日期
+func Today(name string, now time.Time) time.Time {
+	return DateOf(In(name, now))
+}
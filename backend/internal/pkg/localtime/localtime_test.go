@@ -0,0 +1,125 @@
+package localtime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadZone(t *testing.T) {
+	for _, name := range []string{"UTC", "Asia/Shanghai", "America/New_York"} {
+		if _, err := LoadZone(name); err != nil {
+			t.Errorf("LoadZone(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Local", "Mars/Olympus", "+08:00"} {
+		if _, err := LoadZone(name); err == nil {
+			t.Errorf("LoadZone(%q) expected error", name)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"2024-05-20", "2024-05-20", true},
+		{"2024-05-20T00:00:00.000Z", "2024-05-20", true},
+		{"2024-05-20T23:30:00+08:00", "2024-05-20", true},
+		{"2024-02-30", "", false},
+		{"20240520", "", false},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		got, err := ParseDate(c.in)
+		if (err == nil) != c.ok {
+			t.Errorf("ParseDate(%q) error = %v, want ok = %v", c.in, err, c.ok)
+			continue
+		}
+		if c.ok && got.Format(DateLayout) != c.want {
+			t.Errorf("ParseDate(%q) = %s, want %s", c.in, got.Format(DateLayout), c.want)
+		}
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"09:30", "09:30:00", true},
+		{"21:05:09", "21:05:09", true},
+		{"0000-01-01T18:45:00Z", "18:45:00", true},
+		{"2024-05-20T07:15:30.123+08:00", "07:15:30", true},
+		{"24:00", "", false},
+		{"9am", "", false},
+	}
+	for _, c := range cases {
+		got, err := ParseClock(c.in)
+		if (err == nil) != c.ok {
+			t.Errorf("ParseClock(%q) error = %v, want ok = %v", c.in, err, c.ok)
+			continue
+		}
+		if c.ok && got.Format(ClockLayout) != c.want {
+			t.Errorf("ParseClock(%q) = %s, want %s", c.in, got.Format(ClockLayout), c.want)
+		}
+	}
+}
+
+func TestInstant(t *testing.T) {
+	shanghai, _ := LoadZone("Asia/Shanghai")
+	newYork, _ := LoadZone("America/New_York")
+	date, _ := ParseDate("2024-05-20")
+	morning, _ := ParseClock("08:00")
+
+	// 上海 5 月 20 日 08:00 早于纽约 5 月 19 日 21:00（UTC 5 月 20 日 01:00）
+	a := Instant(date, &morning, shanghai)
+	prev, _ := ParseDate("2024-05-19")
+	evening, _ := ParseClock("21:00")
+	b := Instant(prev, &evening, newYork)
+	if !a.Before(b) {
+		t.Errorf("Instant: %s should be before %s", a, b)
+	}
+	if want := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC); !a.Equal(want) {
+		t.Errorf("Instant = %s, want %s", a, want)
+	}
+
+	// 没有时间时取当地零点
+	if got, want := Instant(date, nil, newYork), time.Date(2024, 5, 20, 4, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Instant without clock = %s, want %s", got, want)
+	}
+}
+
+func TestToday(t *testing.T) {
+	// UTC 2024-05-20 17:30 在上海已是次日，在纽约仍是当天
+	now := time.Date(2024, 5, 20, 17, 30, 0, 0, time.UTC)
+	cases := []struct {
+		zone string
+		want string
+	}{
+		{"UTC", "2024-05-20"},
+		{"Asia/Shanghai", "2024-05-21"},
+		{"America/New_York", "2024-05-20"},
+		{"Pacific/Kiritimati", "2024-05-21"},
+		{"", "2024-05-20"},
+		{"Mars/Olympus", "2024-05-20"},
+	}
+	for _, c := range cases {
+		got := Today(c.zone, now)
+		if got.Format(DateLayout) != c.want || got.Location() != time.UTC || got.Hour() != 0 {
+			t.Errorf("Today(%q) = %v, want %s 00:00 UTC", c.zone, got, c.want)
+		}
+	}
+}
+
+func TestIn(t *testing.T) {
+	now := time.Date(2024, 5, 20, 17, 30, 0, 0, time.UTC)
+	if got := In("Asia/Shanghai", now).Hour(); got != 1 {
+		t.Errorf("In(Asia/Shanghai).Hour() = %d, want 1", got)
+	}
+	if got := In("", now).Hour(); got != 17 {
+		t.Errorf("In(\"\").Hour() = %d, want 17", got)
+	}
+}
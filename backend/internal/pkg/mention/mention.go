@@ -0,0 +1,79 @@
+package mention
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match 找出文本中以 @ 提及的名字，只识别 names 中给出的名字（不区分大小写）。
+// 同一位置有多个名字可匹配时取最长的一个，避免 "@小王" 被 "@小" 抢先匹配；
+// 返回值为 names 中的原始写法，按首次出现的顺序去重。
+func Match(text string, names []string) []string {
+	candidates := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) != "" {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 || !strings.Contains(text, "@") {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var matched []string
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '@' {
+			continue
+		}
+		// @ 前紧跟字母或数字时视为邮箱等普通文本
+		if i > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(text[:i])
+			if isWordRune(prev) {
+				continue
+			}
+		}
+
+		rest := text[i+1:]
+		best := ""
+		for _, name := range candidates {
+			if len(name) <= len(best) || len(name) > len(rest) || !strings.EqualFold(rest[:len(name)], name) {
+				continue
+			}
+			if !atBoundary(name, rest[len(name):]) {
+				continue
+			}
+			best = name
+		}
+		if best == "" {
+			continue
+		}
+
+		if !seen[strings.ToLower(best)] {
+			seen[strings.ToLower(best)] = true
+			matched = append(matched, best)
+		}
+		i += len(best)
+	}
+	return matched
+}
+
+// atBoundary 以字母或数字结尾的拉丁名字后面不能紧跟字母或数字，
+// 避免 "@bob" 匹配到 "@bobby"；中文名字之间没有分隔符，不做限制
+func atBoundary(name, after string) bool {
+	last, _ := utf8.DecodeLastRuneInString(name)
+	if !isLatinWordRune(last) {
+		return true
+	}
+	next, size := utf8.DecodeRuneInString(after)
+	return size == 0 || !isLatinWordRune(next)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func isLatinWordRune(r rune) bool {
+	return r < utf8.RuneSelf && isWordRune(r)
+}
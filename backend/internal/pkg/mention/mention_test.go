@@ -0,0 +1,35 @@
+package mention
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	names := []string{"小王", "小王子", "Bob", "alice_w"}
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"@小王 remember this?", []string{"小王"}},
+		{"@小王记得吗", []string{"小王"}},
+		{"@小王子 和 @小王", []string{"小王子", "小王"}},
+		{"hi @bob and @BOB again", []string{"Bob"}},
+		{"@bobby is not bob", nil},
+		{"mail me at me@bob.com", nil},
+		{"(@alice_w) 看这里", []string{"alice_w"}},
+		{"@ 小王", nil},
+		{"no mentions", nil},
+	}
+	for _, c := range cases {
+		if got := Match(c.text, names); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Match(%q) = %v, want %v", c.text, got, c.want)
+		}
+	}
+}
+
+func TestMatchIgnoresBlankNames(t *testing.T) {
+	if got := Match("@ hello", []string{"", "  "}); got != nil {
+		t.Errorf("Match with blank names = %v, want nil", got)
+	}
+}
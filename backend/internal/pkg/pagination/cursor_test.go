@@ -0,0 +1,49 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type testCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	in := testCursor{CreatedAt: time.Date(2025, 5, 20, 13, 14, 0, 123000, time.UTC), ID: uuid.New()}
+
+	encoded, err := EncodeCursor(in)
+	if err != nil {
+		t.Fatalf("EncodeCursor: %v", err)
+	}
+
+	var out testCursor
+	if err := DecodeCursor(encoded, &out); err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !out.CreatedAt.Equal(in.CreatedAt) || out.ID != in.ID {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	var out testCursor
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24"} {
+		if err := DecodeCursor(cursor, &out); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestClampLimit(t *testing.T) {
+	cases := map[int]int{-1: DefaultLimit, 0: DefaultLimit, 10: 10, MaxLimit: MaxLimit, 1000: MaxLimit}
+	for in, want := range cases {
+		if got := ClampLimit(in); got != want {
+			t.Errorf("ClampLimit(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+
+	// ts_headline 使用私有区字符作为高亮占位符，转义正文后再替换为 <mark>，
+	// 避免用户内容中的 HTML 原样返回给客户端
+	headlineStart = "\uE000"
+	headlineStop  = "\uE001"
+)
+
+// HeadlineOptions returns ts_headline options that mark matches with placeholder
+// characters; pass the result through RenderHeadline before returning it to clients
+func HeadlineOptions() string {
+	return fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=30, MinWords=10", headlineStart, headlineStop)
+}
+
+// RenderHeadline HTML-escapes a ts_headline result and turns its placeholders into highlight markers
+func RenderHeadline(headline string) string {
+	return strings.NewReplacer(headlineStart, HighlightStart, headlineStop, HighlightStop).
+		Replace(html.EscapeString(headline))
+}
+
+// ContainsCJK reports whether s contains Chinese, Japanese or Korean characters,
+// which the default text search parsers cannot segment into words
+func ContainsCJK(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// EscapeLike escapes LIKE/ILIKE wildcards so user input is matched literally
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Snippet returns a fragment of text around the first case-insensitive match of query,
+// with the match wrapped in highlight markers and everything else HTML-escaped.
+// radius is measured in characters.
+// It returns an empty string when text does not contain query.
+func Snippet(text, query string, radius int) string {
+	query = strings.TrimSpace(query)
+	if text == "" || query == "" {
+		return ""
+	}
+
+	runes := []rune(text)
+	lowerRunes := []rune(strings.ToLower(text))
+	queryRunes := []rune(strings.ToLower(query))
+	if len(lowerRunes) != len(runes) {
+		// 大小写转换改变了长度时退化为区分大小写匹配
+		lowerRunes = runes
+		queryRunes = []rune(query)
+	}
+
+	idx := indexRunes(lowerRunes, queryRunes)
+	if idx < 0 {
+		return ""
+	}
+	end := idx + len(queryRunes)
+
+	from := max(0, idx-radius)
+	to := min(len(runes), end+radius)
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(html.EscapeString(string(runes[from:idx])))
+	b.WriteString(HighlightStart)
+	b.WriteString(html.EscapeString(string(runes[idx:end])))
+	b.WriteString(HighlightStop)
+	b.WriteString(html.EscapeString(string(runes[end:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func indexRunes(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,54 @@
+package search
+
+import "testing"
+
+func TestContainsCJK(t *testing.T) {
+	cases := map[string]bool{
+		"去海边旅行":      true,
+		"trip to 京都": true,
+		"ソウル":        true,
+		"서울":         true,
+		"beach trip": false,
+		"":           false,
+	}
+	for in, want := range cases {
+		if got := ContainsCJK(in); got != want {
+			t.Errorf("ContainsCJK(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got, want := EscapeLike(`100%_off\`), `100\%\_off\\`; got != want {
+		t.Errorf("EscapeLike = %q, want %q", got, want)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	cases := []struct {
+		text, query string
+		radius      int
+		want        string
+	}{
+		{"今天我们一起去海边看日落", "海边", 2, "…起去<mark>海边</mark>看日…"},
+		{"Sunset at the Beach", "beach", 20, "Sunset at the <mark>Beach</mark>"},
+		{"海边", "海边", 5, "<mark>海边</mark>"},
+		{"nothing here", "海边", 5, ""},
+		{"", "海边", 5, ""},
+		{`beach <script>alert(1)</script> trip`, "beach", 40, "<mark>beach</mark> &lt;script&gt;alert(1)&lt;/script&gt; trip"},
+		{`<img src=x onerror=alert(1)>`, "<img", 40, "<mark>&lt;img</mark> src=x onerror=alert(1)&gt;"},
+	}
+	for _, c := range cases {
+		if got := Snippet(c.text, c.query, c.radius); got != c.want {
+			t.Errorf("Snippet(%q, %q, %d) = %q, want %q", c.text, c.query, c.radius, got, c.want)
+		}
+	}
+}
+
+func TestRenderHeadline(t *testing.T) {
+	headline := "<script>alert(1)</script> " + headlineStart + "beach" + headlineStop + " & sun"
+	want := "&lt;script&gt;alert(1)&lt;/script&gt; <mark>beach</mark> &amp; sun"
+	if got := RenderHeadline(headline); got != want {
+		t.Errorf("RenderHeadline = %q, want %q", got, want)
+	}
+}
@@ -3,10 +3,13 @@ package validator
 import (
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 
+var hexColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
 // 常用邮箱域名白名单
 var allowedEmailDomains = []string{
 	// 国内邮箱
@@ -96,3 +99,37 @@ func IsValidFileType(filename string, allowedTypes []string) bool {
 	}
 	return false
 }
+
+// IsValidHexColor 检查颜色是否为 #RRGGBB 格式
+func IsValidHexColor(color string) bool {
+	return hexColorRegex.MatchString(color)
+}
+
+// IsValidCoordinate 检查经纬度是否在合法范围内
+func IsValidCoordinate(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// IsValidEmoji 检查是否为单个表情（允许肤色、变体选择符、零宽连接等组合序列）
+func IsValidEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > 32 {
+		return false
+	}
+
+	hasSymbol := false
+	keycap := strings.ContainsRune(emoji, '\u20E3')
+	for _, r := range emoji {
+		switch {
+		case unicode.Is(unicode.So, r):
+			hasSymbol = true
+		case r == '\u200D', r == '\u20E3', r == '\uFE0F', r == '\uFE0E':
+		case r >= 0x1F3FB && r <= 0x1F3FF:
+		case r >= 0xE0020 && r <= 0xE007F:
+		case keycap && (r == '#' || r == '*' || (r >= '0' && r <= '9')):
+			hasSymbol = true
+		default:
+			return false
+		}
+	}
+	return hasSymbol
+}
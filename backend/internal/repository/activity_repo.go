@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+func (r *ActivityRepository) Create(activity *model.SpaceActivity) error {
+	return r.db.Create(activity).Error
+}
+
+// FindBySpaceID 按时间倒序获取空间动态，before 为上一页最后一条的位置
+func (r *ActivityRepository) FindBySpaceID(spaceID uuid.UUID, beforeTime *time.Time, beforeID *uuid.UUID, limit int) ([]model.SpaceActivity, error) {
+	var activities []model.SpaceActivity
+	query := r.db.Where("space_id = ?", spaceID)
+	if beforeTime != nil && beforeID != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *beforeTime, *beforeID)
+	}
+	err := query.
+		Preload("Actor").
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&activities).Error
+	return activities, err
+}
+
+// MarkSeen 更新成员的动态已读位置
+func (r *ActivityRepository) MarkSeen(spaceID, userID uuid.UUID, seenAt time.Time) error {
+	return r.db.Model(&model.SpaceMember{}).
+		Where("space_id = ? AND user_id = ?", spaceID, userID).
+		Update("last_seen_at", seenAt).Error
+}
+
+// CountUnreadByUser 统计用户在各个空间中的未读动态数（不含自己产生的动态）
+func (r *ActivityRepository) CountUnreadByUser(userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		SpaceID uuid.UUID
+		Count   int64
+	}
+	err := r.db.Table("space_members").
+		Select("space_members.space_id, COUNT(space_activities.id) AS count").
+		Joins(`JOIN space_activities ON space_activities.space_id = space_members.space_id
+			AND space_activities.created_at > COALESCE(space_members.last_seen_at, space_members.joined_at)
+			AND space_activities.actor_id <> space_members.user_id`).
+		Where("space_members.user_id = ?", userID).
+		Group("space_members.space_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.SpaceID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type AnniversaryRepository struct {
+	db *gorm.DB
+}
+
+func NewAnniversaryRepository(db *gorm.DB) *AnniversaryRepository {
+	return &AnniversaryRepository{db: db}
+}
+
+func (r *AnniversaryRepository) Create(anniversary *model.Anniversary) error {
+	return r.db.Create(anniversary).Error
+}
+
+func (r *AnniversaryRepository) FindByID(id uuid.UUID) (*model.Anniversary, error) {
+	var anniversary model.Anniversary
+	err := r.db.Where("id = ?", id).First(&anniversary).Error
+	return &anniversary, err
+}
+
+func (r *AnniversaryRepository) FindBySpaceID(spaceID uuid.UUID) ([]model.Anniversary, error) {
+	var anniversaries []model.Anniversary
+	err := r.db.Where("space_id = ?", spaceID).Order("date ASC, created_at ASC").Find(&anniversaries).Error
+	return anniversaries, err
+}
+
+func (r *AnniversaryRepository) Update(anniversary *model.Anniversary) error {
+	return r.db.Save(anniversary).Error
+}
+
+func (r *AnniversaryRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.Anniversary{}, id).Error
+}
+
+// FindWithReminders 查询开启了提醒且所在空间未被删除的纪念日
+func (r *AnniversaryRepository) FindWithReminders() ([]model.Anniversary, error) {
+	var anniversaries []model.Anniversary
+	err := r.db.
+		Joins("JOIN spaces ON spaces.id = anniversaries.space_id AND spaces.deleted_at IS NULL").
+		Where("anniversaries.remind_days_before IS NOT NULL").
+		Find(&anniversaries).Error
+	return anniversaries, err
+}
+
+// MarkReminded 记录已为哪一次纪念日发送过提醒
+func (r *AnniversaryRepository) MarkReminded(id uuid.UUID, occurrence time.Time) error {
+	return r.db.Model(&model.Anniversary{}).Where("id = ?", id).Update("reminded_for", occurrence).Error
+}
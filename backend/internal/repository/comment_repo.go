@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) Create(comment *model.EventComment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *CommentRepository) FindByID(id uuid.UUID) (*model.EventComment, error) {
+	var comment model.EventComment
+	err := r.db.Preload("User").Where("id = ?", id).First(&comment).Error
+	return &comment, err
+}
+
+func (r *CommentRepository) Update(comment *model.EventComment) error {
+	return r.db.Omit("User").Save(comment).Error
+}
+
+// DeleteThread 删除评论及其下的全部回复（沿 parent_id 逐层查找）
+func (r *CommentRepository) DeleteThread(commentID uuid.UUID) error {
+	return r.db.Exec(`
+		WITH RECURSIVE thread AS (
+			SELECT id FROM event_comments WHERE id = ?
+			UNION ALL
+			SELECT event_comments.id FROM event_comments JOIN thread ON event_comments.parent_id = thread.id
+		)
+		UPDATE event_comments SET deleted_at = ?
+		WHERE id IN (SELECT id FROM thread) AND deleted_at IS NULL`, commentID, time.Now()).Error
+}
+
+// FindRoots 按时间正序分页获取事件的顶层评论，after 为上一页最后一条的位置
+func (r *CommentRepository) FindRoots(eventID uuid.UUID, afterTime *time.Time, afterID *uuid.UUID, limit int) ([]model.EventComment, error) {
+	var comments []model.EventComment
+	query := r.db.Where("event_id = ? AND root_id IS NULL", eventID)
+	if afterTime != nil && afterID != nil {
+		query = query.Where("(created_at, id) > (?, ?)", *afterTime, *afterID)
+	}
+	err := query.
+		Preload("User").
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&comments).Error
+	return comments, err
+}
+
+// FindReplies 获取若干顶层评论下的全部回复，按时间正序
+func (r *CommentRepository) FindReplies(rootIDs []uuid.UUID) ([]model.EventComment, error) {
+	var replies []model.EventComment
+	if len(rootIDs) == 0 {
+		return replies, nil
+	}
+	err := r.db.
+		Preload("User").
+		Where("root_id IN ?", rootIDs).
+		Order("created_at ASC, id ASC").
+		Find(&replies).Error
+	return replies, err
+}
+
+func (r *CommentRepository) CountByEventID(eventID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.EventComment{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
@@ -1,11 +1,16 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/search"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepository struct {
@@ -29,43 +34,377 @@ func (r *EventRepository) FindByID(id uuid.UUID) (*model.Event, error) {
 		}).
 		Where("id = ?", id).
 		First(&event).Error
-	return &event, err
+	if err != nil {
+		return &event, err
+	}
+
+	events := []model.Event{event}
+	err = r.attachMemberProfiles(events)
+	return &events[0], err
+}
+
+// EventCursor 事件列表的分页位置，对应排序键 (starts_at, id)
+type EventCursor struct {
+	StartsAt time.Time `json:"s"`
+	ID       uuid.UUID `json:"id"`
+}
+
+// NewEventCursor 根据事件生成分页位置
+func NewEventCursor(event *model.Event) EventCursor {
+	return EventCursor{
+		StartsAt: event.StartsAt,
+		ID:       event.ID,
+	}
+}
+
+// visibleEventCondition 返回事件对 viewerID 可见的 SQL 条件及参数：作者总能看到自己的事件，
+// 其他人只能看到已发布（或定时发布时间已到）且可见范围包含自己的事件。
+// viewerID 为 uuid.Nil 表示只看对全体成员公开的事件
+func visibleEventCondition(viewerID uuid.UUID) (string, []interface{}) {
+	return `(events.user_id = ? OR (
+			(events.status = ? OR (events.status = ? AND events.publish_at <= now()))
+			AND (events.visibility = ? OR (events.visibility = ? AND events.visible_to @> jsonb_build_array(?::text)))
+		))`,
+		[]interface{}{
+			viewerID,
+			model.EventStatusPublished, model.EventStatusScheduled,
+			model.EventVisibilityEveryone, model.EventVisibilityMembers, viewerID.String(),
+		}
+}
+
+// visibleTo 将可见性条件应用到 GORM 查询
+func visibleTo(query *gorm.DB, viewerID uuid.UUID) *gorm.DB {
+	condition, args := visibleEventCondition(viewerID)
+	return query.Where(condition, args...)
 }
 
-func (r *EventRepository) FindBySpaceID(spaceID uuid.UUID, limit, offset int) ([]model.Event, error) {
+// 事件列表统一的排序：按开始时刻倒序，不同时区的事件按实际先后排列，最后按 ID 保证顺序稳定
+const eventOrder = "starts_at DESC, id DESC"
+
+// FindBySpaceID 查询空间事件，viewerID 用于标记表情回应是否由当前用户发出
+func (r *EventRepository) FindBySpaceID(spaceID, viewerID uuid.UUID, after *EventCursor, limit int) ([]model.Event, error) {
+	events, err := r.findPage(visibleTo(r.db.Where("space_id = ?", spaceID), viewerID), after, limit)
+	if err != nil {
+		return events, err
+	}
+	return events, r.AttachReactions(events, viewerID)
+}
+
+// FindByDateRange 查询与日期范围有重叠的事件（多天事件只要有一天落在范围内即返回），startDate、endDate 为空表示不限
+func (r *EventRepository) FindByDateRange(spaceID, viewerID uuid.UUID, startDate, endDate *time.Time, after *EventCursor, limit int) ([]model.Event, error) {
+	events, err := r.findPage(r.dateRangeQuery(spaceID, viewerID, startDate, endDate), after, limit)
+	if err != nil {
+		return events, err
+	}
+	return events, r.AttachReactions(events, viewerID)
+}
+
+// CountByDateRange 统计日期范围内 viewerID 可见的事件总数
+func (r *EventRepository) CountByDateRange(spaceID, viewerID uuid.UUID, startDate, endDate *time.Time) (int64, error) {
+	var count int64
+	err := r.dateRangeQuery(spaceID, viewerID, startDate, endDate).Model(&model.Event{}).Count(&count).Error
+	return count, err
+}
+
+func (r *EventRepository) dateRangeQuery(spaceID, viewerID uuid.UUID, startDate, endDate *time.Time) *gorm.DB {
+	query := visibleTo(r.db.Where("space_id = ?", spaceID), viewerID)
+	return overlapsDateRange(query, startDate, endDate)
+}
+
+// overlapsDateRange 筛选与日期范围有重叠的事件，单日事件的结束日期视为开始日期
+func overlapsDateRange(query *gorm.DB, startDate, endDate *time.Time) *gorm.DB {
+	if startDate != nil {
+		query = query.Where(startsOrEndsAfter, *startDate, *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("events.event_date <= ?", *endDate)
+	}
+	return query
+}
+
+// startsOrEndsAfter 事件在某天或之后仍在进行：开始日期不早于该天，或结束日期不早于该天。
+// 结束日期不早于开始日期，两个分支分别走 idx_space_date 和 idx_space_end_date，
+// 不要改写成 COALESCE(end_date, event_date)，那样无法使用索引
+const startsOrEndsAfter = "(events.event_date >= ? OR events.end_date >= ?)"
+
+// TimelineFilter 跨空间时间线的筛选条件，空值表示不限
+type TimelineFilter struct {
+	SpaceIDs  []uuid.UUID
+	StartDate *time.Time
+	EndDate   *time.Time
+	Tags      []string
+	AuthorID  *uuid.UUID
+	Query     string
+	TSConfig  string
+	Trigram   bool
+	ViewerID  uuid.UUID
+}
+
+// FindTimeline 在多个空间中按统一排序查询事件，单条 SQL 完成合并与分页
+func (r *EventRepository) FindTimeline(filter *TimelineFilter, after *EventCursor, limit int) ([]model.Event, error) {
+	if len(filter.SpaceIDs) == 0 {
+		return []model.Event{}, nil
+	}
+
+	query := visibleTo(r.db.Where("space_id IN ?", filter.SpaceIDs), filter.ViewerID)
+	query = overlapsDateRange(query, filter.StartDate, filter.EndDate)
+	if len(filter.Tags) > 0 {
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("tags @> ?::jsonb", string(tags))
+	}
+	if filter.AuthorID != nil {
+		query = query.Where("user_id = ?", *filter.AuthorID)
+	}
+	if filter.Query != "" {
+		if filter.Trigram {
+			query = query.Where("search_text LIKE '%' || lower(?) || '%'", search.EscapeLike(filter.Query))
+		} else {
+			query = query.Where("search_vector @@ websearch_to_tsquery(?::regconfig, ?)", filter.TSConfig, filter.Query)
+		}
+	}
+
+	events, err := r.findPage(query.Preload("Space"), after, limit)
+	if err != nil {
+		return events, err
+	}
+	return events, r.AttachReactions(events, filter.ViewerID)
+}
+
+// FindOnThisDay 查询多个空间中 before 之前、落在给定月份和日期上的事件（“那年今日”）
+// 月、日条件与 idx_events_month_day 表达式索引一致
+func (r *EventRepository) FindOnThisDay(spaceIDs []uuid.UUID, viewerID uuid.UUID, month int, days []int, before time.Time, limit int) ([]model.Event, error) {
+	if len(spaceIDs) == 0 || len(days) == 0 {
+		return []model.Event{}, nil
+	}
+
+	query := visibleTo(r.db.Where("space_id IN ?", spaceIDs), viewerID).
+		Where("EXTRACT(MONTH FROM event_date) = ?", month).
+		Where("EXTRACT(DAY FROM event_date) IN ?", days).
+		Where("event_date < ?", before)
+
+	events, err := r.findPage(query.Preload("Space"), nil, limit)
+	if err != nil {
+		return events, err
+	}
+	return events, r.AttachReactions(events, viewerID)
+}
+
+// findPage 按统一排序做 keyset 分页
+func (r *EventRepository) findPage(query *gorm.DB, after *EventCursor, limit int) ([]model.Event, error) {
+	if after != nil {
+		query = query.Where("(starts_at, id) < (?, ?)", after.StartsAt, after.ID)
+	}
+
 	var events []model.Event
-	err := r.db.
-		Where("space_id = ?", spaceID).
+	err := query.
 		Preload("User").
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_order ASC")
 		}).
-		Order("event_date DESC, event_time DESC").
+		Order(eventOrder).
 		Limit(limit).
-		Offset(offset).
 		Find(&events).Error
-	return events, err
+	if err != nil {
+		return events, err
+	}
+	return events, r.attachMemberProfiles(events)
 }
 
-func (r *EventRepository) FindByDateRange(spaceID uuid.UUID, startDate, endDate time.Time) ([]model.Event, error) {
+// ErrVersionConflict 保存时数据库中的版本号与事件读取时的版本号不一致
+var ErrVersionConflict = errors.New("事件版本冲突")
+
+// Update 只保存事件本身，图片通过 ReplaceImages 单独维护
+func (r *EventRepository) Update(event *model.Event) error {
+	return saveVersioned(r.db, event)
+}
+
+// UpdateWithRevision 在同一事务中保存事件并追加修订记录，修订号按事件递增
+func (r *EventRepository) UpdateWithRevision(event *model.Event, revision *model.EventRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, event); err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&model.EventRevision{}).
+			Where("event_id = ?", event.ID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		revision.EventID = event.ID
+		revision.Revision = last + 1
+		return tx.Omit(clause.Associations).Create(revision).Error
+	})
+}
+
+// saveVersioned 仅当数据库中的版本号仍为 event.Version 时保存，并将版本号加一
+func saveVersioned(tx *gorm.DB, event *model.Event) error {
+	expected := event.Version
+	event.Version = expected + 1
+
+	result := tx.Model(event).
+		Omit(clause.Associations).
+		Where("version = ?", expected).
+		Select("*").
+		Updates(event)
+	if result.Error != nil {
+		event.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		event.Version = expected
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *EventRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.Event{}, id).Error
+}
+
+// 回收站相关操作
+
+// FindTrashedBySpaceID 按删除时间倒序分页获取空间回收站中单独删除、且 viewerID 可见的事件（随空间一起删除的不在此列）
+func (r *EventRepository) FindTrashedBySpaceID(spaceID, viewerID uuid.UUID, beforeTime *time.Time, beforeID *uuid.UUID, limit int) ([]model.Event, error) {
 	var events []model.Event
-	err := r.db.
-		Where("space_id = ? AND event_date >= ? AND event_date <= ?", spaceID, startDate, endDate).
+	query := visibleTo(r.db.Unscoped(), viewerID).
+		Where("space_id = ? AND deleted_at IS NOT NULL", spaceID)
+	if beforeTime != nil && beforeID != nil {
+		query = query.Where("(deleted_at, id) < (?, ?)", *beforeTime, *beforeID)
+	}
+	err := query.
 		Preload("User").
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_order ASC")
 		}).
-		Order("event_date DESC, event_time DESC").
+		Order("deleted_at DESC, id DESC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return events, err
+	}
+	return events, r.attachMemberProfiles(events)
+}
+
+func (r *EventRepository) FindTrashedByID(id uuid.UUID) (*model.Event, error) {
+	var event model.Event
+	err := r.db.Unscoped().
+		Preload("Images").
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&event).Error
+	return &event, err
+}
+
+// FindTrashedBefore 获取删除时间早于 before 的事件，所在空间已删除的由空间清理任务处理
+func (r *EventRepository) FindTrashedBefore(before time.Time) ([]model.Event, error) {
+	var events []model.Event
+	err := r.db.Unscoped().
+		Preload("Images").
+		Joins("JOIN spaces ON spaces.id = events.space_id AND spaces.deleted_at IS NULL").
+		Where("events.deleted_at IS NOT NULL AND events.deleted_at < ?", before).
 		Find(&events).Error
 	return events, err
 }
 
-func (r *EventRepository) Update(event *model.Event) error {
-	return r.db.Save(event).Error
+// FindDueScheduled 查询发布时间已到、尚未转为已发布的定时发布事件
+func (r *EventRepository) FindDueScheduled(now time.Time) ([]model.Event, error) {
+	var events []model.Event
+	err := r.db.
+		Where("status = ? AND publish_at <= ?", model.EventStatusScheduled, now).
+		Order("publish_at ASC").
+		Find(&events).Error
+	return events, err
 }
 
-func (r *EventRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&model.Event{}, id).Error
+// MarkPublished 将到期的定时发布事件改为已发布，返回是否由本次调用完成，多个任务并发时只有一个会成功
+func (r *EventRepository) MarkPublished(id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.Model(&model.Event{}).
+		Where("id = ? AND status = ? AND publish_at <= ?", id, model.EventStatusScheduled, now).
+		Update("status", model.EventStatusPublished)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *EventRepository) Restore(id uuid.UUID) error {
+	return r.db.Unscoped().Model(&model.Event{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// Purge 彻底删除事件及其图片、修订记录、评论、表情回应和提及记录
+func (r *EventRepository) Purge(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("event_id = ?", id).Delete(&model.EventImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("event_id = ?", id).Delete(&model.EventComment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&model.EventReaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&model.Mention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&model.EventRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.Event{}, id).Error
+	})
+}
+
+// attachMemberProfiles 为事件作者填充其在所属空间内的显示身份
+func (r *EventRepository) attachMemberProfiles(events []model.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	pairs := make([][]interface{}, 0, len(events))
+	for _, event := range events {
+		pairs = append(pairs, []interface{}{event.SpaceID, event.UserID})
+	}
+
+	var members []model.SpaceMember
+	if err := r.db.Where("(space_id, user_id) IN ?", pairs).Find(&members).Error; err != nil {
+		return err
+	}
+
+	type memberKey struct{ spaceID, userID uuid.UUID }
+	profiles := make(map[memberKey]*model.MemberProfile, len(members))
+	for i := range members {
+		profiles[memberKey{members[i].SpaceID, members[i].UserID}] = members[i].Profile()
+	}
+
+	for i := range events {
+		events[i].User.SpaceProfile = profiles[memberKey{events[i].SpaceID, events[i].UserID}]
+	}
+	return nil
+}
+
+// AttachReactions 为事件填充表情回应汇总，所有事件合并为一次查询
+func (r *EventRepository) AttachReactions(events []model.Event, viewerID uuid.UUID) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	summaries, err := reactionSummaries(r.db, ids, viewerID)
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		events[i].Reactions = summaries[events[i].ID]
+		if events[i].Reactions == nil {
+			events[i].Reactions = []model.ReactionSummary{}
+		}
+	}
+	return nil
 }
 
 // EventImage 相关操作
@@ -74,8 +413,36 @@ func (r *EventRepository) AddImages(images []model.EventImage) error {
 	return r.db.Create(&images).Error
 }
 
-func (r *EventRepository) DeleteImage(id uuid.UUID) error {
-	return r.db.Delete(&model.EventImage{}, id).Error
+func (r *EventRepository) FindImageByID(id uuid.UUID) (*model.EventImage, error) {
+	var image model.EventImage
+	err := r.db.Where("id = ?", id).First(&image).Error
+	return &image, err
+}
+
+// DeleteImage 删除事件的一张图片，并把剩余图片的排序重新编号为 0..n-1
+func (r *EventRepository) DeleteImage(eventID, imageID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("id = ? AND event_id = ?", imageID, eventID).Delete(&model.EventImage{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE event_images SET sort_order = ranked.position
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY sort_order, uploaded_at) - 1 AS position
+				FROM event_images
+				WHERE event_id = ? AND deleted_at IS NULL
+			) ranked
+			WHERE event_images.id = ranked.id`, eventID).Error
+	})
+}
+
+// CountImageReferences 统计引用某个文件地址（原图或缩略图）的图片记录数，包括回收站中的事件
+func (r *EventRepository) CountImageReferences(url string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.EventImage{}).
+		Where("image_url = ? OR thumbnail_url = ?", url, url).
+		Count(&count).Error
+	return count, err
 }
 
 func (r *EventRepository) FindImagesByEventID(eventID uuid.UUID) ([]model.EventImage, error) {
@@ -83,3 +450,280 @@ func (r *EventRepository) FindImagesByEventID(eventID uuid.UUID) ([]model.EventI
 	err := r.db.Where("event_id = ?", eventID).Order("sort_order ASC").Find(&images).Error
 	return images, err
 }
+
+// ReplaceImages 用 images 替换事件的全部图片：按原图地址保留已有记录并更新排序，新增缺少的，删除多余的
+// 返回被删除的图片记录，便于清理存储中的文件
+func (r *EventRepository) ReplaceImages(eventID uuid.UUID, images []model.EventImage) ([]model.EventImage, error) {
+	var removed []model.EventImage
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []model.EventImage
+		if err := tx.Where("event_id = ?", eventID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byURL := make(map[string]model.EventImage, len(existing))
+		for _, image := range existing {
+			byURL[image.ImageURL] = image
+		}
+
+		kept := make(map[uuid.UUID]bool, len(images))
+		for _, image := range images {
+			image.EventID = eventID
+			if old, ok := byURL[image.ImageURL]; ok && !kept[old.ID] {
+				image.ID = old.ID
+				image.UploadedAt = old.UploadedAt
+				kept[old.ID] = true
+				if err := tx.Omit(clause.Associations).Save(&image).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			image.ID = uuid.Nil
+			if err := tx.Omit(clause.Associations).Create(&image).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, image := range existing {
+			if !kept[image.ID] {
+				if err := tx.Unscoped().Delete(&model.EventImage{}, image.ID).Error; err != nil {
+					return err
+				}
+				removed = append(removed, image)
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// ReorderImages 按 imageIDs 的顺序重新设置事件图片排序
+func (r *EventRepository) ReorderImages(eventID uuid.UUID, imageIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range imageIDs {
+			if err := tx.Model(&model.EventImage{}).
+				Where("id = ? AND event_id = ?", id, eventID).
+				Update("sort_order", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 日历聚合相关操作
+
+type CalendarDayRow struct {
+	EventDate time.Time
+	Count     int64
+	UserIDs   string
+	Thumbnail string
+}
+
+type DayCount struct {
+	Date  time.Time `json:"date"`
+	Count int64     `json:"count"`
+}
+
+// eventDaysJoin 将事件展开为范围内覆盖的每一天（day 列），多天事件在每一天都计入
+const eventDaysJoin = `CROSS JOIN LATERAL generate_series(
+			GREATEST(events.event_date, ?::date),
+			LEAST(COALESCE(events.end_date, events.event_date), ?::date),
+			interval '1 day') AS days(day)`
+
+// CalendarDays 按天聚合日期范围内 viewerID 可见的事件数、参与成员和第一张缩略图，多天事件计入覆盖的每一天
+func (r *EventRepository) CalendarDays(spaceID, viewerID uuid.UUID, startDate, endDate time.Time) ([]CalendarDayRow, error) {
+	visible, args := visibleEventCondition(viewerID)
+	var rows []CalendarDayRow
+	err := r.db.Raw(`
+		SELECT
+			days.day::date AS event_date,
+			COUNT(*) AS count,
+			string_agg(DISTINCT events.user_id::text, ',') AS user_ids,
+			COALESCE((array_agg(cover.thumbnail_url ORDER BY COALESCE(events.event_time, '00:00:00'::time), events.created_at)
+				FILTER (WHERE cover.thumbnail_url IS NOT NULL))[1], '') AS thumbnail
+		FROM events
+		LEFT JOIN LATERAL (
+			SELECT thumbnail_url FROM event_images
+			WHERE event_images.event_id = events.id AND event_images.deleted_at IS NULL
+			ORDER BY sort_order
+			LIMIT 1
+		) cover ON true
+		`+eventDaysJoin+`
+		WHERE events.space_id = ? AND `+startsOrEndsAfter+` AND events.event_date <= ?
+			AND events.deleted_at IS NULL AND `+visible+`
+		GROUP BY days.day
+		ORDER BY days.day`, append([]interface{}{startDate, endDate, spaceID, startDate, startDate, endDate}, args...)...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CountByDay 按天统计日期范围内 viewerID 可见的事件数，多天事件计入覆盖的每一天
+func (r *EventRepository) CountByDay(spaceID, viewerID uuid.UUID, startDate, endDate time.Time) ([]DayCount, error) {
+	visible, args := visibleEventCondition(viewerID)
+	var rows []DayCount
+	err := r.db.Raw(`
+		SELECT days.day::date AS date, COUNT(*) AS count
+		FROM events
+		`+eventDaysJoin+`
+		WHERE events.space_id = ? AND `+startsOrEndsAfter+` AND events.event_date <= ?
+			AND events.deleted_at IS NULL AND `+visible+`
+		GROUP BY days.day
+		ORDER BY days.day`, append([]interface{}{startDate, endDate, spaceID, startDate, startDate, endDate}, args...)...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CalendarSpans 查询与日期范围有重叠、viewerID 可见的多天事件，用于在日历上绘制跨天色条
+func (r *EventRepository) CalendarSpans(spaceID, viewerID uuid.UUID, startDate, endDate time.Time) ([]model.Event, error) {
+	var events []model.Event
+	err := visibleTo(r.db.Where("space_id = ? AND end_date > event_date", spaceID), viewerID).
+		Where("end_date >= ? AND event_date <= ?", startDate, endDate).
+		Select("id", "user_id", "title", "event_date", "event_time", "end_date", "end_time").
+		Order("event_date, end_date DESC, id").
+		Find(&events).Error
+	return events, err
+}
+
+// 全文检索相关操作
+
+type SearchHit struct {
+	ID      uuid.UUID
+	Rank    float64
+	Snippet string
+}
+
+// SearchFullText 使用 tsvector 全文检索，按相关度排序并生成高亮片段
+func (r *EventRepository) SearchFullText(spaceID, viewerID uuid.UUID, tsConfig, query string, limit, offset int) ([]SearchHit, error) {
+	visible, args := visibleEventCondition(viewerID)
+	var hits []SearchHit
+	err := r.db.Raw(`
+		SELECT
+			events.id,
+			ts_rank(events.search_vector, q) AS rank,
+			ts_headline(?::regconfig, concat_ws(' ', events.title, events.description, events.content), q, ?) AS snippet
+		FROM events, websearch_to_tsquery(?::regconfig, ?) AS q
+		WHERE events.space_id = ? AND events.deleted_at IS NULL AND events.search_vector @@ q AND `+visible+`
+		ORDER BY rank DESC, events.starts_at DESC, events.id DESC
+		LIMIT ? OFFSET ?`, append(append([]interface{}{tsConfig, search.HeadlineOptions(), tsConfig, query, spaceID}, args...), limit, offset)...).
+		Scan(&hits).Error
+	for i := range hits {
+		hits[i].Snippet = search.RenderHeadline(hits[i].Snippet)
+	}
+	return hits, err
+}
+
+var (
+	trigramOnce      sync.Once
+	trigramAvailable bool
+)
+
+// hasTrigram 检查数据库是否启用了 pg_trgm 扩展，启动时可能因权限不足而未能创建
+func (r *EventRepository) hasTrigram() bool {
+	trigramOnce.Do(func() {
+		if err := r.db.Raw(`SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')`).Scan(&trigramAvailable).Error; err != nil {
+			trigramAvailable = false
+		}
+	})
+	return trigramAvailable
+}
+
+// SearchTrigram 模糊匹配（适用于中文等无法分词的文本），按词相似度排序。
+// 未启用 pg_trgm 时改为按关键词出现的位置排序，越靠前（如出现在标题中）越相关
+func (r *EventRepository) SearchTrigram(spaceID, viewerID uuid.UUID, query string, limit, offset int) ([]SearchHit, error) {
+	rank := "word_similarity(lower(?), events.search_text)"
+	if !r.hasTrigram() {
+		rank = "1.0 / NULLIF(strpos(events.search_text, lower(?)), 0)"
+	}
+
+	visible, args := visibleEventCondition(viewerID)
+	var hits []SearchHit
+	err := r.db.Raw(`
+		SELECT events.id, `+rank+` AS rank
+		FROM events
+		WHERE events.space_id = ? AND events.deleted_at IS NULL AND events.search_text LIKE '%' || lower(?) || '%' AND `+visible+`
+		ORDER BY rank DESC, events.starts_at DESC, events.id DESC
+		LIMIT ? OFFSET ?`, append(append([]interface{}{query, spaceID, search.EscapeLike(query)}, args...), limit, offset)...).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// FindByIDs 按 ID 批量查询事件，返回顺序与 ids 一致
+func (r *EventRepository) FindByIDs(ids []uuid.UUID) ([]model.Event, error) {
+	if len(ids) == 0 {
+		return []model.Event{}, nil
+	}
+
+	var found []model.Event
+	err := r.db.
+		Where("id IN ?", ids).
+		Preload("User").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]model.Event, len(found))
+	for _, event := range found {
+		byID[event.ID] = event
+	}
+	events := make([]model.Event, 0, len(found))
+	for _, id := range ids {
+		if event, ok := byID[id]; ok {
+			events = append(events, event)
+		}
+	}
+	return events, r.attachMemberProfiles(events)
+}
+
+// 地图聚合相关操作
+
+// BoundingBox 经纬度范围，MinLng > MaxLng 表示跨越 180 度经线
+type BoundingBox struct {
+	MinLng float64
+	MinLat float64
+	MaxLng float64
+	MaxLat float64
+}
+
+type MapCluster struct {
+	Latitude  float64    `json:"latitude"`
+	Longitude float64    `json:"longitude"`
+	Count     int64      `json:"count"`
+	EventID   *uuid.UUID `json:"event_id,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	PlaceName string     `json:"place_name,omitempty"`
+}
+
+// MapClusters 按网格聚合范围内 viewerID 可见的带坐标事件，cellSize 为网格边长（度）
+// 每个网格返回平均坐标和事件数，只有一个事件时附带事件信息
+func (r *EventRepository) MapClusters(spaceID, viewerID uuid.UUID, bbox BoundingBox, cellSize float64, limit int) ([]MapCluster, error) {
+	lngCondition := "longitude BETWEEN ? AND ?"
+	if bbox.MinLng > bbox.MaxLng {
+		lngCondition = "(longitude >= ? OR longitude <= ?)"
+	}
+
+	visible, args := visibleEventCondition(viewerID)
+	var rows []MapCluster
+	err := r.db.Raw(`
+		SELECT
+			AVG(latitude) AS latitude,
+			AVG(longitude) AS longitude,
+			COUNT(*) AS count,
+			CASE WHEN COUNT(*) = 1 THEN MIN(id::text)::uuid END AS event_id,
+			CASE WHEN COUNT(*) = 1 THEN MIN(title) ELSE '' END AS title,
+			CASE WHEN COUNT(*) = 1 THEN MIN(place_name) ELSE '' END AS place_name
+		FROM events
+		WHERE space_id = ? AND deleted_at IS NULL
+			AND latitude IS NOT NULL AND longitude IS NOT NULL
+			AND latitude BETWEEN ? AND ?
+			AND `+lngCondition+`
+			AND `+visible+`
+		GROUP BY floor(latitude / ?), floor(longitude / ?)
+		ORDER BY count DESC
+		LIMIT ?`, append(append([]interface{}{spaceID, bbox.MinLat, bbox.MaxLat, bbox.MinLng, bbox.MaxLng}, args...), cellSize, cellSize, limit)...).
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type MentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) *MentionRepository {
+	return &MentionRepository{db: db}
+}
+
+func (r *MentionRepository) CreateBatch(mentions []model.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	return r.db.Omit("Actor", "Space", "Event", "Comment").Create(&mentions).Error
+}
+
+// FindMentionedUserIDs 查询事件内容（commentID 为空）或某条评论中已提及过的用户
+func (r *MentionRepository) FindMentionedUserIDs(eventID uuid.UUID, commentID *uuid.UUID) ([]uuid.UUID, error) {
+	query := r.db.Model(&model.Mention{}).Where("event_id = ?", eventID)
+	if commentID == nil {
+		query = query.Where("comment_id IS NULL")
+	} else {
+		query = query.Where("comment_id = ?", *commentID)
+	}
+
+	var ids []uuid.UUID
+	err := query.Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// accessibleMentions 用户被提及、且仍能访问的记录：事件和评论未删除、事件对用户可见、用户仍在空间中。
+// 列表和未读数使用同一条件，未读数才不会包含列表中看不到的提及
+func accessibleMentions(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	condition, args := visibleEventCondition(userID)
+	return db.Model(&model.Mention{}).
+		Joins("JOIN events ON events.id = mentions.event_id AND events.deleted_at IS NULL").
+		Where(condition, args...).
+		Joins("JOIN spaces ON spaces.id = mentions.space_id AND spaces.deleted_at IS NULL").
+		Joins("JOIN space_members ON space_members.space_id = mentions.space_id AND space_members.user_id = mentions.user_id").
+		Where("mentions.user_id = ?", userID).
+		Where("mentions.comment_id IS NULL OR EXISTS (SELECT 1 FROM event_comments WHERE event_comments.id = mentions.comment_id AND event_comments.deleted_at IS NULL)")
+}
+
+// FindByUserID 按时间倒序分页查询用户被提及的记录，只返回仍可访问的事件
+func (r *MentionRepository) FindByUserID(userID uuid.UUID, beforeTime *time.Time, beforeID *uuid.UUID, limit int) ([]model.Mention, error) {
+	query := accessibleMentions(r.db, userID)
+	if beforeTime != nil && beforeID != nil {
+		query = query.Where("(mentions.created_at, mentions.id) < (?, ?)", *beforeTime, *beforeID)
+	}
+
+	var mentions []model.Mention
+	err := query.
+		Preload("Actor").
+		Preload("Space").
+		Preload("Event").
+		Preload("Comment").
+		Order("mentions.created_at DESC, mentions.id DESC").
+		Limit(limit).
+		Find(&mentions).Error
+	return mentions, err
+}
+
+// CountUnread 统计用户未读的提及数，只统计列表中能看到的提及
+func (r *MentionRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := accessibleMentions(r.db, userID).
+		Where("mentions.read_at IS NULL").
+		Count(&count).Error
+	return count, err
+}
+
+// MarkAllRead 将用户的提及全部标记为已读
+func (r *MentionRepository) MarkAllRead(userID uuid.UUID) error {
+	return r.db.Model(&model.Mention{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB 返回只生成 SQL、不连接数据库的连接，并记录执行过的查询
+func newDryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=linetime"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	var queries []string
+	err = db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return db, &queries
+}
+
+func TestCountUnreadUsesListFilters(t *testing.T) {
+	db, queries := newDryRunDB(t)
+	repo := NewMentionRepository(db)
+	userID := uuid.New()
+
+	if _, err := repo.FindByUserID(userID, nil, nil, 20); err != nil {
+		t.Fatalf("FindByUserID: %v", err)
+	}
+	if _, err := repo.CountUnread(userID); err != nil {
+		t.Fatalf("CountUnread: %v", err)
+	}
+	if len(*queries) < 2 {
+		t.Fatalf("captured %d queries, want at least 2", len(*queries))
+	}
+
+	list, count := (*queries)[0], (*queries)[len(*queries)-1]
+	if !strings.Contains(count, "count(") {
+		t.Fatalf("last query is not the count: %s", count)
+	}
+	for _, part := range []string{
+		"JOIN events ON events.id = mentions.event_id AND events.deleted_at IS NULL",
+		"JOIN spaces ON spaces.id = mentions.space_id AND spaces.deleted_at IS NULL",
+		"JOIN space_members ON",
+		"event_comments.deleted_at IS NULL",
+		"events.visibility",
+		"events.status",
+	} {
+		if !strings.Contains(list, part) {
+			t.Errorf("FindByUserID query missing %q:\n%s", part, list)
+		}
+		if !strings.Contains(count, part) {
+			t.Errorf("CountUnread query missing %q:\n%s", part, count)
+		}
+	}
+	if !strings.Contains(count, "mentions.read_at IS NULL") {
+		t.Errorf("CountUnread query missing unread filter:\n%s", count)
+	}
+}
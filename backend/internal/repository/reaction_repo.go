@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) *ReactionRepository {
+	return &ReactionRepository{db: db}
+}
+
+// Add 添加表情回应，已存在时忽略
+func (r *ReactionRepository) Add(reaction *model.EventReaction) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction).Error
+}
+
+// Remove 取消表情回应
+func (r *ReactionRepository) Remove(eventID, userID uuid.UUID, emoji string) error {
+	return r.db.
+		Where("event_id = ? AND user_id = ? AND emoji = ?", eventID, userID, emoji).
+		Delete(&model.EventReaction{}).Error
+}
+
+// Summaries 汇总单个事件的表情回应
+func (r *ReactionRepository) Summaries(eventID, viewerID uuid.UUID) ([]model.ReactionSummary, error) {
+	summaries, err := reactionSummaries(r.db, []uuid.UUID{eventID}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if summaries[eventID] == nil {
+		return []model.ReactionSummary{}, nil
+	}
+	return summaries[eventID], nil
+}
+
+// reactionSummaries 一次查询汇总多个事件的表情回应，viewerID 用于标记当前用户是否回应过
+func reactionSummaries(db *gorm.DB, eventIDs []uuid.UUID, viewerID uuid.UUID) (map[uuid.UUID][]model.ReactionSummary, error) {
+	result := make(map[uuid.UUID][]model.ReactionSummary, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		EventID     uuid.UUID
+		Emoji       string
+		Count       int64
+		ReactedByMe bool
+	}
+	err := db.Model(&model.EventReaction{}).
+		Select("event_id, emoji, COUNT(*) AS count, BOOL_OR(user_id = ?) AS reacted_by_me", viewerID).
+		Where("event_id IN ?", eventIDs).
+		Group("event_id, emoji").
+		Order("event_id, MIN(created_at), emoji").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.EventID] = append(result[row.EventID], model.ReactionSummary{
+			Emoji:       row.Emoji,
+			Count:       row.Count,
+			ReactedByMe: row.ReactedByMe,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type RevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+func (r *RevisionRepository) FindByEventID(eventID uuid.UUID) ([]model.EventRevision, error) {
+	var revisions []model.EventRevision
+	err := r.db.
+		Preload("Editor").
+		Where("event_id = ?", eventID).
+		Order("revision DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *RevisionRepository) FindByRevision(eventID uuid.UUID, revision int) (*model.EventRevision, error) {
+	var rev model.EventRevision
+	err := r.db.Where("event_id = ? AND revision = ?", eventID, revision).First(&rev).Error
+	return &rev, err
+}
+
+// DeleteBefore 删除早于 before 的修订记录
+func (r *RevisionRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&model.EventRevision{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExcess 每个事件只保留最新的 keep 条修订记录
+func (r *RevisionRepository) DeleteExcess(keep int) (int64, error) {
+	result := r.db.Exec(`
+		DELETE FROM event_revisions
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY event_id ORDER BY revision DESC) AS position
+				FROM event_revisions
+			) ranked
+			WHERE ranked.position > ?
+		)`, keep)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type ShareRepository struct {
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+func (r *ShareRepository) Create(link *model.ShareLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *ShareRepository) FindByID(id uuid.UUID) (*model.ShareLink, error) {
+	var link model.ShareLink
+	err := r.db.Where("id = ?", id).First(&link).Error
+	return &link, err
+}
+
+func (r *ShareRepository) FindByToken(token string) (*model.ShareLink, error) {
+	var link model.ShareLink
+	err := r.db.Where("token = ?", token).First(&link).Error
+	return &link, err
+}
+
+func (r *ShareRepository) FindBySpaceID(spaceID uuid.UUID) ([]model.ShareLink, error) {
+	var links []model.ShareLink
+	err := r.db.Where("space_id = ?", spaceID).Order("created_at DESC").Find(&links).Error
+	return links, err
+}
+
+func (r *ShareRepository) Revoke(id uuid.UUID) error {
+	return r.db.Model(&model.ShareLink{}).Where("id = ?", id).Update("revoked_at", time.Now()).Error
+}
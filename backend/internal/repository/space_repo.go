@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/qq1477959747/linetime/backend/internal/model"
 	"gorm.io/gorm"
@@ -64,6 +66,13 @@ func (r *SpaceRepository) FindMember(spaceID, userID uuid.UUID) (*model.SpaceMem
 	return &member, err
 }
 
+// UpdateMemberProfile 更新成员在空间内的昵称、颜色和头像，只写入 updates 中给出的列
+func (r *SpaceRepository) UpdateMemberProfile(spaceID, userID uuid.UUID, updates map[string]interface{}) error {
+	return r.db.Model(&model.SpaceMember{}).
+		Where("space_id = ? AND user_id = ?", spaceID, userID).
+		Updates(updates).Error
+}
+
 func (r *SpaceRepository) GetMembers(spaceID uuid.UUID) ([]model.SpaceMember, error) {
 	var members []model.SpaceMember
 	err := r.db.Where("space_id = ?", spaceID).Preload("User").Find(&members).Error
@@ -73,29 +82,187 @@ func (r *SpaceRepository) GetMembers(spaceID uuid.UUID) ([]model.SpaceMember, er
 func (r *SpaceRepository) IsUserInSpace(spaceID, userID uuid.UUID) (bool, error) {
 	var count int64
 	err := r.db.Model(&model.SpaceMember{}).
-		Where("space_id = ? AND user_id = ?", spaceID, userID).
+		Joins("JOIN spaces ON spaces.id = space_members.space_id AND spaces.deleted_at IS NULL").
+		Where("space_members.space_id = ? AND space_members.user_id = ?", spaceID, userID).
 		Count(&count).Error
 	return count > 0, err
 }
 
-// DeleteWithRelations 删除空间及其所有关联数据（成员、事件）
-func (r *SpaceRepository) DeleteWithRelations(spaceID uuid.UUID) error {
+// 归档与回收站相关操作
+
+func (r *SpaceRepository) SetArchivedAt(spaceID uuid.UUID, archivedAt *time.Time) error {
+	return r.db.Model(&model.Space{}).Where("id = ?", spaceID).Update("archived_at", archivedAt).Error
+}
+
+func (r *SpaceRepository) IsArchived(spaceID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Space{}).
+		Where("id = ? AND archived_at IS NOT NULL", spaceID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// MoveToTrash 将空间及其事件移入回收站，成员关系保留以便恢复。
+// 同时清除以该空间为默认空间的用户设置，并在成员关系上记下，恢复时还原
+func (r *SpaceRepository) MoveToTrash(spaceID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Model(&model.SpaceMember{}).
+			Where("space_id = ? AND user_id IN (SELECT id FROM users WHERE default_space_id = ?)", spaceID, spaceID).
+			Update("was_default", true).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.User{}).Where("default_space_id = ?", spaceID).Update("default_space_id", nil).Error; err != nil {
+			return err
+		}
+
+		// 事件与空间使用同一个删除时间，恢复时只恢复随空间一起删除的事件
+		if err := tx.Model(&model.Event{}).Where("space_id = ?", spaceID).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Space{}).Where("id = ?", spaceID).Update("deleted_at", now).Error
+	})
+}
+
+// FindTrashedByID 查找回收站中的空间
+func (r *SpaceRepository) FindTrashedByID(id uuid.UUID) (*model.Space, error) {
+	var space model.Space
+	err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&space).Error
+	return &space, err
+}
+
+// FindTrashedByOwner 获取用户回收站中的空间
+func (r *SpaceRepository) FindTrashedByOwner(ownerID uuid.UUID) ([]model.Space, error) {
+	var spaces []model.Space
+	err := r.db.Unscoped().
+		Where("owner_id = ? AND deleted_at IS NOT NULL", ownerID).
+		Order("deleted_at DESC").
+		Find(&spaces).Error
+	return spaces, err
+}
+
+// FindTrashedBefore 获取删除时间早于 before 的空间（待彻底清理）
+func (r *SpaceRepository) FindTrashedBefore(before time.Time) ([]model.Space, error) {
+	var spaces []model.Space
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Find(&spaces).Error
+	return spaces, err
+}
+
+// Restore 从回收站恢复空间及随其一起删除的事件。
+// 删除前以该空间为默认空间、且之后没有另设默认空间的成员，恢复其默认空间
+func (r *SpaceRepository) Restore(space *model.Space) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).
+			Where("default_space_id IS NULL AND id IN (SELECT user_id FROM space_members WHERE space_id = ? AND was_default)", space.ID).
+			Update("default_space_id", space.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.SpaceMember{}).
+			Where("space_id = ? AND was_default", space.ID).
+			Update("was_default", false).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&model.Event{}).
+			Where("space_id = ? AND deleted_at = ?", space.ID, space.DeletedAt.Time).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&model.Space{}).Where("id = ?", space.ID).Update("deleted_at", nil).Error
+	})
+}
+
+// FindImageURLs 获取空间下所有事件（包括已删除事件）的原图与缩略图地址，
+// 其他空间的事件仍在引用的地址不返回，避免删除别人的文件
+func (r *SpaceRepository) FindImageURLs(spaceID uuid.UUID) ([]string, error) {
+	var urls []string
+	err := r.db.Raw(`
+		SELECT DISTINCT u.url
+		FROM event_images
+		JOIN events ON events.id = event_images.event_id
+		CROSS JOIN LATERAL (VALUES (event_images.image_url), (event_images.thumbnail_url)) AS u(url)
+		WHERE events.space_id = ? AND u.url <> ''
+		AND NOT EXISTS (
+			SELECT 1 FROM event_images other
+			JOIN events other_events ON other_events.id = other.event_id
+			WHERE other_events.space_id <> ?
+			AND (other.image_url = u.url OR other.thumbnail_url = u.url)
+		)`, spaceID, spaceID).Scan(&urls).Error
+	return urls, err
+}
+
+// PurgeWithRelations 彻底删除空间及其所有关联数据（成员、事件、事件图片、修订记录、评论、表情回应、提及、纪念日、动态、分享链接、标签）
+func (r *SpaceRepository) PurgeWithRelations(spaceID uuid.UUID) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 删除事件图片
+		if err := tx.Unscoped().
+			Where("event_id IN (?)", tx.Unscoped().Model(&model.Event{}).Select("id").Where("space_id = ?", spaceID)).
+			Delete(&model.EventImage{}).Error; err != nil {
+			return err
+		}
+
+		// 删除事件评论
+		if err := tx.Unscoped().
+			Where("event_id IN (?)", tx.Unscoped().Model(&model.Event{}).Select("id").Where("space_id = ?", spaceID)).
+			Delete(&model.EventComment{}).Error; err != nil {
+			return err
+		}
+
+		// 删除事件表情回应
+		if err := tx.
+			Where("event_id IN (?)", tx.Unscoped().Model(&model.Event{}).Select("id").Where("space_id = ?", spaceID)).
+			Delete(&model.EventReaction{}).Error; err != nil {
+			return err
+		}
+
+		// 删除纪念日
+		if err := tx.Where("space_id = ?", spaceID).Delete(&model.Anniversary{}).Error; err != nil {
+			return err
+		}
+
+		// 删除提及记录
+		if err := tx.Where("space_id = ?", spaceID).Delete(&model.Mention{}).Error; err != nil {
+			return err
+		}
+
+		// 删除事件修订记录
+		if err := tx.
+			Where("event_id IN (?)", tx.Unscoped().Model(&model.Event{}).Select("id").Where("space_id = ?", spaceID)).
+			Delete(&model.EventRevision{}).Error; err != nil {
+			return err
+		}
+
+		// 删除空间事件
+		if err := tx.Unscoped().Where("space_id = ?", spaceID).Delete(&model.Event{}).Error; err != nil {
+			return err
+		}
+
 		// 删除空间成员
 		if err := tx.Where("space_id = ?", spaceID).Delete(&model.SpaceMember{}).Error; err != nil {
 			return err
 		}
 
-		// 删除空间事件
-		if err := tx.Where("space_id = ?", spaceID).Delete(&model.Event{}).Error; err != nil {
+		// 删除空间动态
+		if err := tx.Where("space_id = ?", spaceID).Delete(&model.SpaceActivity{}).Error; err != nil {
 			return err
 		}
 
-		// 删除空间
-		if err := tx.Delete(&model.Space{}, spaceID).Error; err != nil {
+		// 删除分享链接
+		if err := tx.Where("space_id = ?", spaceID).Delete(&model.ShareLink{}).Error; err != nil {
 			return err
 		}
 
-		return nil
+		// 删除标签
+		if err := tx.Where("space_id = ?", spaceID).Delete(&model.SpaceTag{}).Error; err != nil {
+			return err
+		}
+
+		// 删除空间
+		return tx.Unscoped().Delete(&model.Space{}, spaceID).Error
 	})
 }
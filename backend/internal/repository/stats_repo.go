@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+type PeriodCount struct {
+	Period string `json:"period"`
+	Count  int64  `json:"count"`
+}
+
+type MemberCount struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Nickname string    `json:"nickname,omitempty"`
+	Count    int64     `json:"count"`
+}
+
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type EventTotals struct {
+	EventCount int64      `json:"event_count"`
+	DayCount   int64      `json:"day_count"`
+	PhotoCount int64      `json:"photo_count"`
+	FirstDate  *time.Time `json:"first_date"`
+	LastDate   *time.Time `json:"last_date"`
+}
+
+type Streak struct {
+	Days      int64      `json:"days"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+// publicEvents 统计结果按空间缓存并由全体成员共用，只统计已公开的事件，
+// 返回追加了可见性参数的查询参数
+func publicEvents(args ...interface{}) (string, []interface{}) {
+	visible, visibleArgs := visibleEventCondition(uuid.Nil)
+	return visible, append(args, visibleArgs...)
+}
+
+// CountByPeriod 按时间段统计事件数，format 为 PostgreSQL to_char 格式（如 YYYY-MM）
+func (r *StatsRepository) CountByPeriod(spaceID uuid.UUID, format string) ([]PeriodCount, error) {
+	visible, args := publicEvents(format, spaceID)
+	var rows []PeriodCount
+	err := r.db.Raw(`
+		SELECT to_char(event_date, ?) AS period, COUNT(*) AS count
+		FROM events
+		WHERE space_id = ? AND deleted_at IS NULL AND `+visible+`
+		GROUP BY period
+		ORDER BY period`, args...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CountByMember 按成员统计事件数
+func (r *StatsRepository) CountByMember(spaceID uuid.UUID) ([]MemberCount, error) {
+	visible, args := publicEvents(spaceID)
+	var rows []MemberCount
+	err := r.db.Raw(`
+		SELECT events.user_id, users.username, COALESCE(space_members.nickname, '') AS nickname, COUNT(*) AS count
+		FROM events
+		JOIN users ON users.id = events.user_id
+		LEFT JOIN space_members ON space_members.space_id = events.space_id AND space_members.user_id = events.user_id
+		WHERE events.space_id = ? AND events.deleted_at IS NULL AND `+visible+`
+		GROUP BY events.user_id, users.username, space_members.nickname
+		ORDER BY count DESC`, args...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// TopTags 统计使用最多的标签
+func (r *StatsRepository) TopTags(spaceID uuid.UUID, limit int) ([]ValueCount, error) {
+	visible, args := publicEvents(spaceID)
+	var rows []ValueCount
+	err := r.db.Raw(`
+		SELECT tag AS value, COUNT(*) AS count
+		FROM events, jsonb_array_elements_text(CASE WHEN jsonb_typeof(events.tags) = 'array' THEN events.tags ELSE '[]'::jsonb END) AS tag
+		WHERE events.space_id = ? AND events.deleted_at IS NULL AND `+visible+`
+		GROUP BY tag
+		ORDER BY count DESC, tag
+		LIMIT ?`, append(args, limit)...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// TopLocations 统计出现最多的地点
+func (r *StatsRepository) TopLocations(spaceID uuid.UUID, limit int) ([]ValueCount, error) {
+	visible, args := publicEvents(spaceID)
+	var rows []ValueCount
+	err := r.db.Raw(`
+		SELECT location AS value, COUNT(*) AS count
+		FROM events
+		WHERE space_id = ? AND deleted_at IS NULL AND COALESCE(location, '') <> '' AND `+visible+`
+		GROUP BY location
+		ORDER BY count DESC, location
+		LIMIT ?`, append(args, limit)...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Totals 统计事件总数、记录天数、照片数以及首末事件日期
+func (r *StatsRepository) Totals(spaceID uuid.UUID) (*EventTotals, error) {
+	visible, args := publicEvents(spaceID)
+	_, outerArgs := publicEvents(spaceID)
+	args = append(args, outerArgs...)
+	var totals EventTotals
+	err := r.db.Raw(`
+		SELECT
+			COUNT(*) AS event_count,
+			COUNT(DISTINCT event_date) AS day_count,
+			(SELECT COUNT(*) FROM event_images
+				JOIN events ON events.id = event_images.event_id
+				WHERE events.space_id = ? AND events.deleted_at IS NULL AND event_images.deleted_at IS NULL AND `+visible+`) AS photo_count,
+			MIN(event_date) AS first_date,
+			MAX(event_date) AS last_date
+		FROM events
+		WHERE space_id = ? AND deleted_at IS NULL AND `+visible, args...).
+		Scan(&totals).Error
+	return &totals, err
+}
+
+// LongestStreak 计算连续有记录的最长天数
+func (r *StatsRepository) LongestStreak(spaceID uuid.UUID) (*Streak, error) {
+	visible, args := publicEvents(spaceID)
+	var streak Streak
+	err := r.db.Raw(`
+		WITH days AS (
+			SELECT DISTINCT event_date FROM events
+			WHERE space_id = ? AND deleted_at IS NULL AND `+visible+`
+		), islands AS (
+			SELECT event_date, event_date - (ROW_NUMBER() OVER (ORDER BY event_date))::int AS grp
+			FROM days
+		)
+		SELECT COUNT(*) AS days, MIN(event_date) AS start_date, MAX(event_date) AS end_date
+		FROM islands
+		GROUP BY grp
+		ORDER BY days DESC, start_date DESC
+		LIMIT 1`, args...).
+		Scan(&streak).Error
+	return &streak, err
+}
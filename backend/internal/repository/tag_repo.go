@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) Create(tag *model.SpaceTag) error {
+	return r.db.Create(tag).Error
+}
+
+func (r *TagRepository) FindByID(id uuid.UUID) (*model.SpaceTag, error) {
+	var tag model.SpaceTag
+	err := r.db.Where("id = ?", id).First(&tag).Error
+	return &tag, err
+}
+
+func (r *TagRepository) FindBySpaceID(spaceID uuid.UUID) ([]model.SpaceTag, error) {
+	var tags []model.SpaceTag
+	err := r.db.Where("space_id = ?", spaceID).Order("lower(name)").Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagRepository) Update(tag *model.SpaceTag) error {
+	return r.db.Save(tag).Error
+}
+
+// EnsureTags 登记尚未存在的标签，已存在的（忽略大小写）跳过
+func (r *TagRepository) EnsureTags(spaceID uuid.UUID, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags := make([]model.SpaceTag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, model.SpaceTag{SpaceID: spaceID, Name: name, Aliases: []string{}})
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error
+}
+
+// UsageCounts 统计空间内每个标签被 viewerID 可见的未删除事件使用的次数
+func (r *TagRepository) UsageCounts(spaceID, viewerID uuid.UUID) ([]ValueCount, error) {
+	visible, args := visibleEventCondition(viewerID)
+	var rows []ValueCount
+	err := r.db.Raw(`
+		SELECT tag AS value, COUNT(*) AS count
+		FROM events, jsonb_array_elements_text(CASE WHEN jsonb_typeof(events.tags) = 'array' THEN events.tags ELSE '[]'::jsonb END) AS tag
+		WHERE events.space_id = ? AND events.deleted_at IS NULL AND `+visible+`
+		GROUP BY tag`, append([]interface{}{spaceID}, args...)...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Save 更新标签，并把事件中 from 里的写法统一改为标签名
+func (r *TagRepository) Save(tag *model.SpaceTag, from []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(tag).Error; err != nil {
+			return err
+		}
+		return rewriteEventTags(tx, tag.SpaceID, from, tag.Name)
+	})
+}
+
+// Merge 将 sources 合并到 target：删除来源标签，事件中 from 里的写法改为目标标签名
+func (r *TagRepository) Merge(target *model.SpaceTag, sources []model.SpaceTag, from []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := make([]uuid.UUID, 0, len(sources))
+		for _, source := range sources {
+			ids = append(ids, source.ID)
+		}
+		if err := tx.Where("id IN ?", ids).Delete(&model.SpaceTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(target).Error; err != nil {
+			return err
+		}
+		return rewriteEventTags(tx, target.SpaceID, from, target.Name)
+	})
+}
+
+// Delete 删除标签，并从事件中移除 from 里的写法
+func (r *TagRepository) Delete(tag *model.SpaceTag, from []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.SpaceTag{}, tag.ID).Error; err != nil {
+			return err
+		}
+		return rewriteEventTags(tx, tag.SpaceID, from, "")
+	})
+}
+
+// rewriteEventTags 将空间内事件（含回收站中的）标签里属于 from 的项替换为 to，to 为空表示移除
+// 匹配忽略大小写，替换后按原顺序去重，保证同一事件不会出现重复标签
+func rewriteEventTags(tx *gorm.DB, spaceID uuid.UUID, from []string, to string) error {
+	if len(from) == 0 {
+		return nil
+	}
+
+	lowered := make([]string, 0, len(from))
+	for _, name := range from {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	return tx.Exec(`
+		UPDATE events SET version = version + 1, tags = (
+			SELECT COALESCE(jsonb_agg(tag ORDER BY ord), '[]'::jsonb)
+			FROM (
+				SELECT tag, MIN(ord) AS ord
+				FROM (
+					SELECT CASE WHEN lower(elem) IN ? THEN NULLIF(?, '') ELSE elem END AS tag, ord
+					FROM jsonb_array_elements_text(events.tags) WITH ORDINALITY AS t(elem, ord)
+				) mapped
+				WHERE tag IS NOT NULL
+				GROUP BY tag
+			) deduped
+		)
+		WHERE space_id = ? AND jsonb_typeof(tags) = 'array'
+			AND EXISTS (SELECT 1 FROM jsonb_array_elements_text(events.tags) AS e(elem) WHERE lower(elem) IN ?)`,
+		lowered, to, spaceID, lowered).Error
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/qq1477959747/linetime/backend/internal/model"
 	"gorm.io/gorm"
@@ -78,3 +80,28 @@ func (r *UserRepository) UpdateGoogleID(userID uuid.UUID, googleID string) error
 func (r *UserRepository) UpdatePassword(userID uuid.UUID, passwordHash string) error {
 	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("password_hash", passwordHash).Error
 }
+
+// UpdateOnThisDayDigest 开启或关闭“那年今日”每日邮件
+func (r *UserRepository) UpdateOnThisDayDigest(userID uuid.UUID, enabled bool) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("on_this_day_digest", enabled).Error
+}
+
+// UpdateTimeZone 修改用户资料中的时区
+func (r *UserRepository) UpdateTimeZone(userID uuid.UUID, timeZone string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("time_zone", timeZone).Error
+}
+
+// FindDigestRecipients 查询订阅了每日邮件且在 today 之前最后一次发送的用户，各用户的“当天”由调用方按时区判断
+func (r *UserRepository) FindDigestRecipients(today time.Time) ([]model.User, error) {
+	var users []model.User
+	err := r.db.
+		Where("on_this_day_digest = ?", true).
+		Where("digest_sent_on IS NULL OR digest_sent_on < ?", today).
+		Find(&users).Error
+	return users, err
+}
+
+// MarkDigestSent 记录每日邮件的发送日期
+func (r *UserRepository) MarkDigestSent(userID uuid.UUID, day time.Time) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("digest_sent_on", day).Error
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+type ActivityService struct {
+	activityRepo *repository.ActivityRepository
+	spaceRepo    *repository.SpaceRepository
+}
+
+func NewActivityService(activityRepo *repository.ActivityRepository, spaceRepo *repository.SpaceRepository) *ActivityService {
+	return &ActivityService{
+		activityRepo: activityRepo,
+		spaceRepo:    spaceRepo,
+	}
+}
+
+type activityCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+type ActivityFeedResponse struct {
+	pagination.Page[model.SpaceActivity]
+	LastSeenAt *time.Time `json:"last_seen_at"`
+}
+
+// Record 记录空间动态，失败只记录日志，不影响主流程
+func (s *ActivityService) Record(spaceID, actorID uuid.UUID, activityType model.ActivityType, targetID *uuid.UUID, summary string) {
+	activity := &model.SpaceActivity{
+		SpaceID:  spaceID,
+		ActorID:  actorID,
+		Type:     activityType,
+		TargetID: targetID,
+		Summary:  truncateRunes(summary, 200),
+	}
+	if err := s.activityRepo.Create(activity); err != nil {
+		log.Printf("记录空间动态失败: %v", err)
+	}
+}
+
+// GetSpaceActivity 分页获取空间动态
+func (s *ActivityService) GetSpaceActivity(spaceID, userID uuid.UUID, cursor string, limit int) (*ActivityFeedResponse, error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	member, err := s.spaceRepo.FindMember(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var beforeTime *time.Time
+	var beforeID *uuid.UUID
+	if cursor != "" {
+		var c activityCursor
+		if err := pagination.DecodeCursor(cursor, &c); err != nil {
+			return nil, err
+		}
+		beforeTime, beforeID = &c.CreatedAt, &c.ID
+	}
+
+	limit = pagination.ClampLimit(limit)
+	activities, err := s.activityRepo.FindBySpaceID(spaceID, beforeTime, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ActivityFeedResponse{LastSeenAt: member.LastSeenAt}
+	if len(activities) > limit {
+		activities = activities[:limit]
+		last := activities[len(activities)-1]
+		next, err := pagination.EncodeCursor(activityCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = next
+		result.HasMore = true
+	}
+	result.Items = activities
+
+	return result, nil
+}
+
+// MarkSeen 将空间动态标记为已读
+func (s *ActivityService) MarkSeen(spaceID, userID uuid.UUID) error {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("无权访问该空间")
+	}
+
+	return s.activityRepo.MarkSeen(spaceID, userID, time.Now())
+}
+
+// UnreadCounts 获取用户在各个空间中的未读动态数
+func (s *ActivityService) UnreadCounts(userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	return s.activityRepo.CountUnreadByUser(userID)
+}
+
+// truncateRunes 按字符截断，避免截断中文
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
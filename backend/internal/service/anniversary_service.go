@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/config"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/anniversary"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+const (
+	maxAnniversaryTitleLength = 100
+	maxRemindDaysBefore       = 60
+)
+
+type AnniversaryService struct {
+	anniversaryRepo *repository.AnniversaryRepository
+	spaceRepo       *repository.SpaceRepository
+	emailSender     EmailSender
+}
+
+func NewAnniversaryService(anniversaryRepo *repository.AnniversaryRepository, spaceRepo *repository.SpaceRepository, emailSender EmailSender) *AnniversaryService {
+	return &AnniversaryService{
+		anniversaryRepo: anniversaryRepo,
+		spaceRepo:       spaceRepo,
+		emailSender:     emailSender,
+	}
+}
+
+type CreateAnniversaryRequest struct {
+	Title            string                 `json:"title" binding:"required"`
+	Date             string                 `json:"date" binding:"required"` // YYYY-MM-DD
+	Recurrence       anniversary.Recurrence `json:"recurrence"`
+	RemindDaysBefore *int                   `json:"remind_days_before"` // 为空时使用系统默认的提前天数
+}
+
+type UpdateAnniversaryRequest struct {
+	Title            *string                 `json:"title"`
+	Date             *string                 `json:"date"`
+	Recurrence       *anniversary.Recurrence `json:"recurrence"`
+	RemindDaysBefore *int                    `json:"remind_days_before"`
+	ClearReminder    bool                    `json:"clear_reminder"` // 关闭提醒
+}
+
+// GetAnniversaries 获取空间纪念日，附带截至今天的“第 N 天”和倒计时
+func (s *AnniversaryService) GetAnniversaries(spaceID, userID uuid.UUID) ([]model.Anniversary, error) {
+	if err := s.checkMember(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	anniversaries, err := s.anniversaryRepo.FindBySpaceID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := s.spaceToday(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range anniversaries {
+		withCountdown(&anniversaries[i], today)
+	}
+	return anniversaries, nil
+}
+
+// GetAnniversary 获取单个纪念日的倒计时，date 为空时按今天计算
+func (s *AnniversaryService) GetAnniversary(spaceID, anniversaryID, userID uuid.UUID, date *time.Time) (*model.Anniversary, error) {
+	if err := s.checkMember(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.findAnniversary(spaceID, anniversaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if date != nil {
+		return withCountdown(item, *date), nil
+	}
+	today, err := s.spaceToday(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return withCountdown(item, today), nil
+}
+
+// CreateAnniversary 创建纪念日
+func (s *AnniversaryService) CreateAnniversary(spaceID, userID uuid.UUID, req *CreateAnniversaryRequest) (*model.Anniversary, error) {
+	if err := s.checkWritable(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	date, err := localtime.ParseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &model.Anniversary{
+		SpaceID:          spaceID,
+		CreatedBy:        userID,
+		Title:            req.Title,
+		Date:             date,
+		Recurrence:       req.Recurrence,
+		RemindDaysBefore: req.RemindDaysBefore,
+	}
+	if item.Recurrence == "" {
+		item.Recurrence = anniversary.Yearly
+	}
+	if item.RemindDaysBefore == nil {
+		days := config.AppConfig.Anniversary.DefaultRemindDays
+		item.RemindDaysBefore = &days
+	}
+	if err := validateAnniversary(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.anniversaryRepo.Create(item); err != nil {
+		return nil, err
+	}
+	today, err := s.spaceToday(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return withCountdown(item, today), nil
+}
+
+// UpdateAnniversary 修改纪念日（创建者或空间 owner）
+func (s *AnniversaryService) UpdateAnniversary(spaceID, anniversaryID, userID uuid.UUID, req *UpdateAnniversaryRequest) (*model.Anniversary, error) {
+	if err := s.checkWritable(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.findAnniversary(spaceID, anniversaryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanManage(item, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+	if req.Date != nil {
+		date, err := localtime.ParseDate(*req.Date)
+		if err != nil {
+			return nil, err
+		}
+		item.Date = date
+	}
+	if req.Recurrence != nil {
+		item.Recurrence = *req.Recurrence
+	}
+	if req.ClearReminder {
+		item.RemindDaysBefore = nil
+	} else if req.RemindDaysBefore != nil {
+		item.RemindDaysBefore = req.RemindDaysBefore
+	}
+	// 日期或重复方式变化后，下一次纪念日需要重新提醒
+	if req.Date != nil || req.Recurrence != nil || req.RemindDaysBefore != nil {
+		item.RemindedFor = nil
+	}
+	if err := validateAnniversary(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.anniversaryRepo.Update(item); err != nil {
+		return nil, err
+	}
+	today, err := s.spaceToday(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return withCountdown(item, today), nil
+}
+
+// DeleteAnniversary 删除纪念日（创建者或空间 owner）
+func (s *AnniversaryService) DeleteAnniversary(spaceID, anniversaryID, userID uuid.UUID) error {
+	if err := s.checkWritable(spaceID, userID); err != nil {
+		return err
+	}
+
+	item, err := s.findAnniversary(spaceID, anniversaryID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCanManage(item, userID); err != nil {
+		return err
+	}
+
+	return s.anniversaryRepo.Delete(item.ID)
+}
+
+// SendReminders 对即将到来的纪念日给空间成员发送提醒邮件，每次纪念日只提醒一次
+// 与每日邮件使用相同的发送时间，避免深夜打扰；纪念日属于空间，日期和发送时间按空间所有者的时区计算
+func (s *AnniversaryService) SendReminders(ctx context.Context) error {
+	now := time.Now()
+
+	anniversaries, err := s.anniversaryRepo.FindWithReminders()
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for i := range anniversaries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		item := &anniversaries[i]
+
+		space, err := s.spaceRepo.FindByID(item.SpaceID)
+		if err != nil {
+			log.Printf("查询纪念日 %s 所在空间失败: %v", item.ID, err)
+			continue
+		}
+		local := localtime.In(space.Owner.TimeZone, now)
+		if local.Hour() < config.AppConfig.Digest.SendHour {
+			continue
+		}
+
+		countdown := anniversary.Compute(item.Date, item.Recurrence, localtime.DateOf(local))
+		if countdown.NextDate == nil || *countdown.DaysUntil > *item.RemindDaysBefore {
+			continue
+		}
+		if item.RemindedFor != nil && item.RemindedFor.Equal(*countdown.NextDate) {
+			continue
+		}
+
+		s.remind(space, item, &countdown)
+		if err := s.anniversaryRepo.MarkReminded(item.ID, *countdown.NextDate); err != nil {
+			log.Printf("记录纪念日 %s 提醒状态失败: %v", item.ID, err)
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		log.Printf("已发送 %d 个纪念日提醒", sent)
+	}
+	return nil
+}
+
+// remind 给空间全部成员发送提醒。单个成员发送失败只记录日志，不重试，
+// 以免已收到提醒的成员重复收到邮件
+func (s *AnniversaryService) remind(space *model.Space, item *model.Anniversary, countdown *anniversary.Countdown) {
+	if s.emailSender == nil {
+		return
+	}
+
+	for _, member := range space.Members {
+		data := &AnniversaryEmail{
+			Username:   displayName(&member),
+			SpaceName:  space.Name,
+			SpaceID:    space.ID,
+			Title:      item.Title,
+			Date:       countdown.NextDate.Format("2006-01-02"),
+			DaysUntil:  *countdown.DaysUntil,
+			Occurrence: countdown.Occurrence,
+			Recurrence: string(item.Recurrence),
+		}
+		if err := s.emailSender.SendAnniversaryReminder(member.User.Email, data); err != nil {
+			log.Printf("发送纪念日 %s 提醒给用户 %s 失败: %v", item.ID, member.UserID, err)
+		}
+	}
+}
+
+func (s *AnniversaryService) findAnniversary(spaceID, anniversaryID uuid.UUID) (*model.Anniversary, error) {
+	item, err := s.anniversaryRepo.FindByID(anniversaryID)
+	if err != nil || item.SpaceID != spaceID {
+		return nil, errors.New("纪念日不存在")
+	}
+	return item, nil
+}
+
+func (s *AnniversaryService) checkMember(spaceID, userID uuid.UUID) error {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("无权访问该空间")
+	}
+	return nil
+}
+
+func (s *AnniversaryService) checkWritable(spaceID, userID uuid.UUID) error {
+	if err := s.checkMember(spaceID, userID); err != nil {
+		return err
+	}
+	archived, err := s.spaceRepo.IsArchived(spaceID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return ErrSpaceArchived
+	}
+	return nil
+}
+
+func (s *AnniversaryService) checkCanManage(item *model.Anniversary, userID uuid.UUID) error {
+	if item.CreatedBy == userID {
+		return nil
+	}
+	space, err := s.spaceRepo.FindByID(item.SpaceID)
+	if err != nil {
+		return err
+	}
+	if space.OwnerID != userID {
+		return errors.New("只有创建者或空间创建者可以修改纪念日")
+	}
+	return nil
+}
+
+func validateAnniversary(item *model.Anniversary) error {
+	item.Title = strings.TrimSpace(item.Title)
+	if item.Title == "" {
+		return errors.New("纪念日名称不能为空")
+	}
+	if utf8.RuneCountInString(item.Title) > maxAnniversaryTitleLength {
+		return errors.New("纪念日名称不能超过100个字符")
+	}
+	if !item.Recurrence.IsValid() {
+		return errors.New("重复方式只能是 none、yearly 或 monthly")
+	}
+	if item.RemindDaysBefore != nil && (*item.RemindDaysBefore < 0 || *item.RemindDaysBefore > maxRemindDaysBefore) {
+		return errors.New("提前提醒天数需在 0 到 60 之间")
+	}
+	return nil
+}
+
+// spaceToday 返回空间所有者时区中的今天，与提醒邮件使用同一个日期，避免午夜前后相差一天
+func (s *AnniversaryService) spaceToday(spaceID uuid.UUID) (time.Time, error) {
+	space, err := s.spaceRepo.FindByID(spaceID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return localtime.Today(space.Owner.TimeZone, time.Now()), nil
+}
+
+func withCountdown(item *model.Anniversary, today time.Time) *model.Anniversary {
+	countdown := anniversary.Compute(item.Date, item.Recurrence, today)
+	item.Countdown = &countdown
+	return item
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+type CalendarService struct {
+	eventRepo *repository.EventRepository
+	spaceRepo *repository.SpaceRepository
+}
+
+func NewCalendarService(eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository) *CalendarService {
+	return &CalendarService{
+		eventRepo: eventRepo,
+		spaceRepo: spaceRepo,
+	}
+}
+
+type CalendarMember struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Nickname  string    `json:"nickname,omitempty"`
+	Color     string    `json:"color,omitempty"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+}
+
+type CalendarDay struct {
+	Date      string           `json:"date"`
+	Count     int64            `json:"count"`
+	Thumbnail string           `json:"thumbnail,omitempty"`
+	Members   []CalendarMember `json:"members"`
+}
+
+// CalendarSpan 跨越多天的事件，StartDate、EndDate 为事件完整的起止日期，可能超出当月
+type CalendarSpan struct {
+	EventID   uuid.UUID      `json:"event_id"`
+	Title     string         `json:"title"`
+	StartDate string         `json:"start_date"`
+	EndDate   string         `json:"end_date"`
+	Member    CalendarMember `json:"member"`
+}
+
+type CalendarMonthResponse struct {
+	Year  int            `json:"year"`
+	Month int            `json:"month"`
+	Days  []CalendarDay  `json:"days"`
+	Spans []CalendarSpan `json:"spans"`
+}
+
+type HeatmapDay struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+type CalendarHeatmapResponse struct {
+	Year     int          `json:"year"`
+	Total    int64        `json:"total"`
+	MaxCount int64        `json:"max_count"`
+	Days     []HeatmapDay `json:"days"`
+}
+
+// GetMonth 获取某月每天的事件数、第一张缩略图和参与成员，以及与当月重叠的多天事件
+func (s *CalendarService) GetMonth(spaceID, userID uuid.UUID, year, month int) (*CalendarMonthResponse, error) {
+	if err := s.checkMember(spaceID, userID); err != nil {
+		return nil, err
+	}
+	if year < 1 || month < 1 || month > 12 {
+		return nil, errors.New("无效的年份或月份")
+	}
+
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, -1)
+
+	rows, err := s.eventRepo.CalendarDays(spaceID, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	spanEvents, err := s.eventRepo.CalendarSpans(spaceID, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberLookup(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]CalendarDay, 0, len(rows))
+	for _, row := range rows {
+		day := CalendarDay{
+			Date:      row.EventDate.Format("2006-01-02"),
+			Count:     row.Count,
+			Thumbnail: row.Thumbnail,
+			Members:   []CalendarMember{},
+		}
+		for _, id := range strings.Split(row.UserIDs, ",") {
+			userID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			day.Members = append(day.Members, lookupMember(members, userID))
+		}
+		days = append(days, day)
+	}
+
+	spans := make([]CalendarSpan, 0, len(spanEvents))
+	for _, event := range spanEvents {
+		spans = append(spans, CalendarSpan{
+			EventID:   event.ID,
+			Title:     event.Title,
+			StartDate: event.EventDate.Format("2006-01-02"),
+			EndDate:   event.LastDate().Format("2006-01-02"),
+			Member:    lookupMember(members, event.UserID),
+		})
+	}
+
+	return &CalendarMonthResponse{Year: year, Month: month, Days: days, Spans: spans}, nil
+}
+
+// GetHeatmap 获取一整年每天的事件数，用于热力图
+func (s *CalendarService) GetHeatmap(spaceID, userID uuid.UUID, year int) (*CalendarHeatmapResponse, error) {
+	if err := s.checkMember(spaceID, userID); err != nil {
+		return nil, err
+	}
+	if year < 1 {
+		return nil, errors.New("无效的年份")
+	}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	rows, err := s.eventRepo.CountByDay(spaceID, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CalendarHeatmapResponse{Year: year, Days: make([]HeatmapDay, 0, len(rows))}
+	for _, row := range rows {
+		result.Days = append(result.Days, HeatmapDay{Date: row.Date.Format("2006-01-02"), Count: row.Count})
+		result.Total += row.Count
+		if row.Count > result.MaxCount {
+			result.MaxCount = row.Count
+		}
+	}
+
+	return result, nil
+}
+
+func (s *CalendarService) checkMember(spaceID, userID uuid.UUID) error {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("无权访问该空间")
+	}
+	return nil
+}
+
+// lookupMember 查找成员资料，已离开空间的成员仍然保留其贡献
+func lookupMember(members map[uuid.UUID]CalendarMember, userID uuid.UUID) CalendarMember {
+	if member, ok := members[userID]; ok {
+		return member
+	}
+	return CalendarMember{UserID: userID}
+}
+
+// memberLookup 加载空间成员及其空间内资料
+func (s *CalendarService) memberLookup(spaceID uuid.UUID) (map[uuid.UUID]CalendarMember, error) {
+	members, err := s.spaceRepo.GetMembers(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := make(map[uuid.UUID]CalendarMember, len(members))
+	for _, member := range members {
+		avatar := member.User.AvatarURL
+		if member.AvatarURL != "" {
+			avatar = member.AvatarURL
+		}
+		lookup[member.UserID] = CalendarMember{
+			UserID:    member.UserID,
+			Username:  member.User.Username,
+			Nickname:  member.Nickname,
+			Color:     member.Color,
+			AvatarURL: avatar,
+		}
+	}
+	return lookup, nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+const maxCommentLength = 2000
+
+type CommentService struct {
+	commentRepo     *repository.CommentRepository
+	eventRepo       *repository.EventRepository
+	spaceRepo       *repository.SpaceRepository
+	activityService *ActivityService
+	mentionService  *MentionService
+}
+
+func NewCommentService(commentRepo *repository.CommentRepository, eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository, activityService *ActivityService, mentionService *MentionService) *CommentService {
+	return &CommentService{
+		commentRepo:     commentRepo,
+		eventRepo:       eventRepo,
+		spaceRepo:       spaceRepo,
+		activityService: activityService,
+		mentionService:  mentionService,
+	}
+}
+
+type CreateCommentRequest struct {
+	Content  string     `json:"content" binding:"required"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type commentCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+type CommentPage struct {
+	pagination.Page[model.EventComment]
+	Total int64 `json:"total"`
+}
+
+// GetComments 按时间正序分页获取顶层评论，每条顶层评论附带其全部回复
+func (s *CommentService) GetComments(eventID, userID uuid.UUID, cursor string, limit int) (*CommentPage, error) {
+	if _, err := s.findEvent(eventID, userID); err != nil {
+		return nil, err
+	}
+
+	var afterTime *time.Time
+	var afterID *uuid.UUID
+	if cursor != "" {
+		var c commentCursor
+		if err := pagination.DecodeCursor(cursor, &c); err != nil {
+			return nil, err
+		}
+		afterTime, afterID = &c.CreatedAt, &c.ID
+	}
+
+	limit = pagination.ClampLimit(limit)
+	roots, err := s.commentRepo.FindRoots(eventID, afterTime, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CommentPage{}
+	if len(roots) > limit {
+		roots = roots[:limit]
+		last := roots[len(roots)-1]
+		next, err := pagination.EncodeCursor(commentCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+		page.HasMore = true
+	}
+
+	rootIDs := make([]uuid.UUID, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+	replies, err := s.commentRepo.FindReplies(rootIDs)
+	if err != nil {
+		return nil, err
+	}
+	byRoot := make(map[uuid.UUID][]model.EventComment, len(roots))
+	for _, reply := range replies {
+		byRoot[*reply.RootID] = append(byRoot[*reply.RootID], reply)
+	}
+	for i := range roots {
+		roots[i].Replies = byRoot[roots[i].ID]
+	}
+	page.Items = roots
+
+	if page.Total, err = s.commentRepo.CountByEventID(eventID); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// CreateComment 发表评论或回复
+func (s *CommentService) CreateComment(eventID, userID uuid.UUID, req *CreateCommentRequest) (*model.EventComment, error) {
+	event, err := s.findEvent(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return nil, err
+	}
+
+	content, err := normalizeComment(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &model.EventComment{
+		EventID: eventID,
+		UserID:  userID,
+		Content: content,
+	}
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(*req.ParentID)
+		if err != nil || parent.EventID != eventID {
+			return nil, errors.New("回复的评论不存在")
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = parent.RootID
+		if comment.RootID == nil {
+			comment.RootID = &parent.ID
+		}
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	// 动态对全体成员可见，仅部分成员可见的事件的评论不记入动态
+	if s.activityService != nil && event.IsPublic(time.Now()) {
+		s.activityService.Record(event.SpaceID, userID, model.ActivityCommentAdded, &event.ID, event.Title)
+	}
+	if s.mentionService != nil {
+		s.mentionService.NotifyCommentMentions(event, comment)
+	}
+
+	return s.commentRepo.FindByID(comment.ID)
+}
+
+// UpdateComment 修改评论（仅作者）
+func (s *CommentService) UpdateComment(eventID, commentID, userID uuid.UUID, req *UpdateCommentRequest) (*model.EventComment, error) {
+	event, err := s.findEvent(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.findComment(eventID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != userID {
+		return nil, errors.New("只能修改自己的评论")
+	}
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return nil, err
+	}
+
+	content, err := normalizeComment(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	comment.Content = content
+	comment.EditedAt = &now
+
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+	if s.mentionService != nil {
+		s.mentionService.NotifyCommentMentions(event, comment)
+	}
+	return comment, nil
+}
+
+// DeleteComment 删除评论及其回复（作者或空间 owner）
+func (s *CommentService) DeleteComment(eventID, commentID, userID uuid.UUID) error {
+	event, err := s.findEvent(eventID, userID)
+	if err != nil {
+		return err
+	}
+	comment, err := s.findComment(eventID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != userID {
+		space, err := s.spaceRepo.FindByID(event.SpaceID)
+		if err != nil {
+			return err
+		}
+		if space.OwnerID != userID {
+			return errors.New("只有评论作者或空间创建者可以删除评论")
+		}
+	}
+
+	return s.commentRepo.DeleteThread(comment.ID)
+}
+
+// findEvent 查询事件并检查当前用户是否为空间成员
+func (s *CommentService) findEvent(eventID, userID uuid.UUID) (*model.Event, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("事件不存在")
+	}
+
+	isMember, err := s.spaceRepo.IsUserInSpace(event.SpaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该事件")
+	}
+	if !event.IsVisibleTo(userID, time.Now()) {
+		return nil, errors.New("事件不存在")
+	}
+	return event, nil
+}
+
+func (s *CommentService) findComment(eventID, commentID uuid.UUID) (*model.EventComment, error) {
+	comment, err := s.commentRepo.FindByID(commentID)
+	if err != nil || comment.EventID != eventID {
+		return nil, errors.New("评论不存在")
+	}
+	return comment, nil
+}
+
+func (s *CommentService) ensureSpaceWritable(spaceID uuid.UUID) error {
+	archived, err := s.spaceRepo.IsArchived(spaceID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return ErrSpaceArchived
+	}
+	return nil
+}
+
+func normalizeComment(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("评论内容不能为空")
+	}
+	if utf8.RuneCountInString(content) > maxCommentLength {
+		return "", errors.New("评论不能超过2000个字符")
+	}
+	return content, nil
+}
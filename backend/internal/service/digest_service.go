@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/qq1477959747/linetime/backend/config"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+	"github.com/qq1477959747/linetime/backend/internal/storage"
+)
+
+type DigestService struct {
+	userRepo        *repository.UserRepository
+	timelineService *TimelineService
+	storage         *storage.MinIOStorage
+	emailSender     EmailSender
+}
+
+func NewDigestService(userRepo *repository.UserRepository, timelineService *TimelineService, storage *storage.MinIOStorage, emailSender EmailSender) *DigestService {
+	return &DigestService{
+		userRepo:        userRepo,
+		timelineService: timelineService,
+		storage:         storage,
+		emailSender:     emailSender,
+	}
+}
+
+// 全球最早进入新一天的时区偏移（UTC+14）
+const earliestZoneOffset = 14 * time.Hour
+
+// SendOnThisDayDigests 给订阅了“那年今日”的用户发送当天的回忆邮件，每人每天最多一封
+// 由定时任务反复调用，“当天”和发送时间都按用户自己的时区计算；没有回忆的用户不发邮件，但同样记为已处理
+func (s *DigestService) SendOnThisDayDigests(ctx context.Context) error {
+	now := time.Now()
+
+	// 先按最早的时区取出今天可能要发送的用户，再逐个按用户时区判断
+	users, err := s.userRepo.FindDigestRecipients(localtime.DateOf(now.UTC().Add(earliestZoneOffset)))
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for i := range users {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		user := &users[i]
+
+		local := localtime.In(user.TimeZone, now)
+		if local.Hour() < config.AppConfig.Digest.SendHour {
+			continue
+		}
+		today := localtime.DateOf(local)
+		if user.DigestSentOn != nil && !user.DigestSentOn.Before(today) {
+			continue
+		}
+
+		memories, err := s.timelineService.GetOnThisDay(user.ID, &today)
+		if err != nil {
+			log.Printf("查询用户 %s 的那年今日失败: %v", user.ID, err)
+			continue
+		}
+
+		if len(memories.Years) > 0 {
+			if err := s.emailSender.SendOnThisDayDigest(user.Email, s.buildOnThisDayEmail(ctx, user, memories)); err != nil {
+				log.Printf("发送那年今日邮件给用户 %s 失败: %v", user.ID, err)
+				continue
+			}
+			sent++
+		}
+
+		if err := s.userRepo.MarkDigestSent(user.ID, today); err != nil {
+			log.Printf("记录用户 %s 的每日邮件发送状态失败: %v", user.ID, err)
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("已发送 %d 封那年今日邮件", sent)
+	}
+	return nil
+}
+
+func (s *DigestService) buildOnThisDayEmail(ctx context.Context, user *model.User, memories *OnThisDayResponse) *OnThisDayEmail {
+	data := &OnThisDayEmail{
+		Username: user.Username,
+		Date:     memories.Date,
+		Years:    make([]OnThisDayEmailYear, 0, len(memories.Years)),
+	}
+	for _, year := range memories.Years {
+		item := OnThisDayEmailYear{Year: year.Year, YearsAgo: year.YearsAgo}
+		for _, event := range year.Events {
+			var thumbnail string
+			if len(event.Images) > 0 {
+				thumbnail = s.presignThumbnail(ctx, event.Images[0].ThumbnailURL)
+			}
+			item.Events = append(item.Events, OnThisDayEmailEvent{
+				Title:        event.Title,
+				SpaceName:    event.Space.Name,
+				SpaceID:      event.SpaceID,
+				EventID:      event.ID,
+				ThumbnailURL: thumbnail,
+			})
+		}
+		data.Years = append(data.Years, item)
+	}
+	return data
+}
+
+// presignThumbnail 为邮件中的缩略图生成长期有效的签名地址，邮件客户端无法访问存储桶的原始地址。
+// 签名失败时不展示缩略图，邮件中的链接仍会跳转到应用内查看
+func (s *DigestService) presignThumbnail(ctx context.Context, url string) string {
+	if s.storage == nil || url == "" {
+		return ""
+	}
+	signed, err := s.storage.GetFileURL(ctx, storage.GetObjectNameFromURL(url), storage.MaxURLExpiry)
+	if err != nil {
+		log.Printf("生成邮件缩略图地址失败 %s: %v", url, err)
+		return ""
+	}
+	return signed
+}
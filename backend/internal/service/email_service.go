@@ -1,8 +1,11 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
+	"mime"
 	"net/smtp"
+	"strings"
 
 	"github.com/qq1477959747/linetime/backend/config"
 )
@@ -11,6 +14,9 @@ import (
 type EmailSender interface {
 	SendVerificationCode(to, code string) error
 	SendLoginCode(to, code string) error
+	SendMentionNotification(to string, data *MentionEmail) error
+	SendOnThisDayDigest(to string, data *OnThisDayEmail) error
+	SendAnniversaryReminder(to string, data *AnniversaryEmail) error
 }
 
 // SMTPEmailService implements EmailSender using SMTP
@@ -20,6 +26,7 @@ type SMTPEmailService struct {
 	username string
 	password string
 	from     string
+	appURL   string
 }
 
 // NewSMTPEmailService creates a new SMTP email service
@@ -30,6 +37,7 @@ func NewSMTPEmailService() *SMTPEmailService {
 		username: config.AppConfig.SMTP.Username,
 		password: config.AppConfig.SMTP.Password,
 		from:     config.AppConfig.SMTP.From,
+		appURL:   strings.TrimRight(config.AppConfig.SMTP.AppURL, "/"),
 	}
 }
 
@@ -129,12 +137,65 @@ func (s *SMTPEmailService) SendLoginCode(to, code string) error {
 	return nil
 }
 
+// SendMentionNotification sends an email telling the user they were mentioned
+func (s *SMTPEmailService) SendMentionNotification(to string, data *MentionEmail) error {
+	data.Link = fmt.Sprintf("%s/spaces/%s/events/%s", s.appURL, data.SpaceID, data.EventID)
+	return s.sendTemplate(to, fmt.Sprintf("%s 在「%s」中提到了你", data.ActorName, data.EventTitle), "mention", data)
+}
+
+// SendOnThisDayDigest sends the daily "on this day" memories email
+func (s *SMTPEmailService) SendOnThisDayDigest(to string, data *OnThisDayEmail) error {
+	for i := range data.Years {
+		for j := range data.Years[i].Events {
+			event := &data.Years[i].Events[j]
+			event.Link = fmt.Sprintf("%s/spaces/%s/events/%s", s.appURL, event.SpaceID, event.EventID)
+		}
+	}
+	return s.sendTemplate(to, fmt.Sprintf("LineTime 那年今日 · %s", data.Date), "on_this_day", data)
+}
+
+// SendAnniversaryReminder sends an upcoming anniversary reminder
+func (s *SMTPEmailService) SendAnniversaryReminder(to string, data *AnniversaryEmail) error {
+	data.Link = fmt.Sprintf("%s/spaces/%s", s.appURL, data.SpaceID)
+	subject := fmt.Sprintf("「%s」还有 %d 天", data.Title, data.DaysUntil)
+	if data.DaysUntil == 0 {
+		subject = fmt.Sprintf("今天是「%s」", data.Title)
+	}
+	return s.sendTemplate(to, subject, "anniversary", data)
+}
+
+// sendTemplate renders a named template from emailTemplates and sends it as HTML
+func (s *SMTPEmailService) sendTemplate(to, subject, name string, data interface{}) error {
+	var body bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&body, name, data); err != nil {
+		return fmt.Errorf("渲染邮件模板失败: %w", err)
+	}
+
+	msg := fmt.Sprintf("From: LineTime <%s>\r\n", s.from)
+	msg += fmt.Sprintf("To: %s\r\n", to)
+	msg += fmt.Sprintf("Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	msg += "MIME-Version: 1.0\r\n"
+	msg += "Content-Type: text/html; charset=UTF-8\r\n"
+	msg += "\r\n"
+	msg += body.String()
+
+	auth := smtp.PlainAuth("", s.username, s.password, s.host)
+	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	if err := smtp.SendMail(addr, auth, s.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	return nil
+}
+
 // MockEmailService is a mock implementation for testing
 type MockEmailService struct {
 	SentEmails []struct {
 		To   string
 		Code string
 	}
+	SentMentions  []*MentionEmail
+	SentDigests   []*OnThisDayEmail
+	SentReminders []*AnniversaryEmail
 }
 
 // NewMockEmailService creates a new mock email service
@@ -159,3 +220,21 @@ func (s *MockEmailService) SendLoginCode(to, code string) error {
 	}{To: to, Code: code})
 	return nil
 }
+
+// SendMentionNotification records the mention instead of sending
+func (s *MockEmailService) SendMentionNotification(to string, data *MentionEmail) error {
+	s.SentMentions = append(s.SentMentions, data)
+	return nil
+}
+
+// SendOnThisDayDigest records the digest instead of sending
+func (s *MockEmailService) SendOnThisDayDigest(to string, data *OnThisDayEmail) error {
+	s.SentDigests = append(s.SentDigests, data)
+	return nil
+}
+
+// SendAnniversaryReminder records the reminder instead of sending
+func (s *MockEmailService) SendAnniversaryReminder(to string, data *AnniversaryEmail) error {
+	s.SentReminders = append(s.SentReminders, data)
+	return nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"html/template"
+
+	"github.com/google/uuid"
+)
+
+// MentionEmail @ 提及通知邮件的内容，Link 由发送方根据前端地址生成
+type MentionEmail struct {
+	ActorName  string
+	SpaceName  string
+	SpaceID    uuid.UUID
+	EventID    uuid.UUID
+	EventTitle string
+	Excerpt    string
+	Link       string
+}
+
+// OnThisDayEmail “那年今日”每日邮件的内容
+type OnThisDayEmail struct {
+	Username string
+	Date     string
+	Years    []OnThisDayEmailYear
+}
+
+type OnThisDayEmailYear struct {
+	Year     int
+	YearsAgo int
+	Events   []OnThisDayEmailEvent
+}
+
+type OnThisDayEmailEvent struct {
+	Title        string
+	SpaceName    string
+	SpaceID      uuid.UUID
+	EventID      uuid.UUID
+	ThumbnailURL string
+	Link         string
+}
+
+// AnniversaryEmail 纪念日提醒邮件的内容
+type AnniversaryEmail struct {
+	Username   string
+	SpaceName  string
+	SpaceID    uuid.UUID
+	Title      string
+	Date       string
+	DaysUntil  int
+	Occurrence int
+	Recurrence string
+	Link       string
+}
+
+// emailTemplates 通知类邮件模板，使用 html/template 以转义用户输入的内容
+var emailTemplates = template.Must(template.New("email").Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+{{end}}
+
+{{define "footer"}}        <hr style="border: none; border-top: 1px solid #e5e7eb; margin: 20px 0;">
+        <p style="color: #6b7280; font-size: 12px;">此邮件由 LineTime 系统自动发送，请勿回复。</p>
+    </div>
+</body>
+</html>
+{{end}}
+
+{{define "mention"}}{{template "header"}}
+        <h2 style="color: #2563eb;">有人提到了你</h2>
+        <p><strong>{{.ActorName}}</strong> 在空间「{{.SpaceName}}」的事件「{{.EventTitle}}」中提到了你：</p>
+        <div style="background-color: #f3f4f6; padding: 16px; margin: 20px 0; border-radius: 8px; white-space: pre-wrap;">{{.Excerpt}}</div>
+        <p><a href="{{.Link}}" style="color: #2563eb;">查看事件</a></p>
+{{template "footer"}}{{end}}
+
+{{define "on_this_day"}}{{template "header"}}
+        <h2 style="color: #d97706;">那年今日 · {{.Date}}</h2>
+        <p>{{.Username}}，你好：</p>
+        <p>这些是往年的今天留下的回忆：</p>
+        {{range .Years}}
+        <h3 style="margin: 24px 0 8px;">{{.YearsAgo}} 年前（{{.Year}}）</h3>
+        {{range .Events}}
+        <div style="background-color: #f3f4f6; padding: 12px 16px; margin: 8px 0; border-radius: 8px;">
+            {{if .ThumbnailURL}}<img src="{{.ThumbnailURL}}" alt="" style="max-width: 100%; border-radius: 4px; margin-bottom: 8px;">{{end}}
+            <a href="{{.Link}}" style="color: #111827; font-weight: bold; text-decoration: none;">{{.Title}}</a>
+            <div style="color: #6b7280; font-size: 12px;">{{.SpaceName}}</div>
+        </div>
+        {{end}}
+        {{end}}
+        <p style="color: #6b7280; font-size: 12px;">如不想再收到此邮件，可在个人设置中关闭“那年今日”提醒。</p>
+{{template "footer"}}{{end}}
+
+{{define "anniversary"}}{{template "header"}}
+        <h2 style="color: #db2777;">纪念日提醒</h2>
+        <p>{{.Username}}，你好：</p>
+        <div style="background-color: #fdf2f8; padding: 20px; text-align: center; margin: 20px 0; border-radius: 8px;">
+            <div style="font-size: 20px; font-weight: bold;">{{.Title}}</div>
+            {{if gt .Occurrence 0}}<div style="margin-top: 4px;">{{if eq .Recurrence "monthly"}}第 {{.Occurrence}} 个月{{else}}{{.Occurrence}} 周年{{end}}</div>{{end}}
+            <div style="margin-top: 8px; color: #6b7280;">{{.Date}}</div>
+            <div style="margin-top: 12px; font-size: 28px; font-weight: bold; color: #db2777;">{{if eq .DaysUntil 0}}就是今天{{else}}还有 {{.DaysUntil}} 天{{end}}</div>
+        </div>
+        <p>来自空间「{{.SpaceName}}」，<a href="{{.Link}}" style="color: #db2777;">去看看</a></p>
+{{template "footer"}}{{end}}
+`))
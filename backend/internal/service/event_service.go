@@ -1,61 +1,151 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/config"
 	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/validator"
 	"github.com/qq1477959747/linetime/backend/internal/repository"
+	"github.com/qq1477959747/linetime/backend/internal/storage"
 )
 
 type EventService struct {
-	eventRepo *repository.EventRepository
-	spaceRepo *repository.SpaceRepository
+	eventRepo       *repository.EventRepository
+	spaceRepo       *repository.SpaceRepository
+	revisionRepo    *repository.RevisionRepository
+	tagService      *TagService
+	storage         *storage.MinIOStorage
+	activityService *ActivityService
+	mentionService  *MentionService
+	userRepo        *repository.UserRepository
 }
 
-func NewEventService(eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository) *EventService {
+func NewEventService(eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository, revisionRepo *repository.RevisionRepository, tagService *TagService, storage *storage.MinIOStorage, activityService *ActivityService, mentionService *MentionService, userRepo *repository.UserRepository) *EventService {
 	return &EventService{
-		eventRepo: eventRepo,
-		spaceRepo: spaceRepo,
+		eventRepo:       eventRepo,
+		spaceRepo:       spaceRepo,
+		revisionRepo:    revisionRepo,
+		tagService:      tagService,
+		storage:         storage,
+		activityService: activityService,
+		mentionService:  mentionService,
+		userRepo:        userRepo,
 	}
 }
 
 type CreateEventRequest struct {
-	SpaceID     uuid.UUID  `json:"space_id" binding:"required"`
-	EventDate   time.Time  `json:"event_date" binding:"required"`
-	EventTime   *time.Time `json:"event_time"`
-	Title       string     `json:"title"`
-	Content     string     `json:"content"`
-	Description string     `json:"description"`
-	Location    string     `json:"location"`
-	Tags        []string   `json:"tags"`
-	Images      []string   `json:"images"`
+	SpaceID uuid.UUID `json:"space_id" binding:"required"`
+	// 日期为 YYYY-MM-DD、时间为 HH:MM[:SS]，都是 TimeZone 中的本地值
+	EventDate   string   `json:"event_date" binding:"required"`
+	EventTime   *string  `json:"event_time"`
+	EndDate     *string  `json:"end_date"` // 多天事件（如旅行）的结束日期
+	EndTime     *string  `json:"end_time"`
+	TimeZone    string   `json:"time_zone"` // IANA 时区，为空时使用用户资料中的时区
+	Title       string   `json:"title"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	Location    string   `json:"location"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	PlaceName   string   `json:"place_name"`
+	Tags        []string `json:"tags"`
+	// Photos 优先；只提供 Images 时按原图地址推算缩略图
+	Photos []EventImageUpload `json:"photos"`
+	Images []string           `json:"images"`
+	// Status 为空时直接发布；scheduled 需要同时提供 PublishAt
+	Status    model.EventStatus `json:"status"`
+	PublishAt *time.Time        `json:"publish_at"`
+	// Visibility 为空时全体成员可见；members 需要同时提供 VisibleTo
+	Visibility model.EventVisibility `json:"visibility"`
+	VisibleTo  []uuid.UUID           `json:"visible_to"`
 }
 
 type EventImageUpload struct {
 	ImageURL     string `json:"image_url" binding:"required"`
 	ThumbnailURL string `json:"thumbnail_url" binding:"required"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
 	SortOrder    int    `json:"sort_order"`
 }
 
+type ReorderImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids" binding:"required"`
+}
+
 type UpdateEventRequest struct {
-	EventDate   *time.Time `json:"event_date"`
-	EventTime   *time.Time `json:"event_time"`
-	Title       *string    `json:"title"`
-	Content     *string    `json:"content"`
-	Description *string    `json:"description"`
-	Location    *string    `json:"location"`
-	Tags        []string   `json:"tags"`
-	Images      []string   `json:"images"`
+	Version     *int     `json:"version"` // 客户端读取时的版本号，也可通过 If-Match 提供
+	EventDate   *string  `json:"event_date"`
+	EventTime   *string  `json:"event_time"` // 空字符串表示清除时间
+	EndDate     *string  `json:"end_date"`
+	EndTime     *string  `json:"end_time"`
+	ClearEnd    bool     `json:"clear_end"` // 清除结束日期和时间，恢复为单日事件
+	TimeZone    *string  `json:"time_zone"`
+	Title       *string  `json:"title"`
+	Content     *string  `json:"content"`
+	Description *string  `json:"description"`
+	Location    *string  `json:"location"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	PlaceName   *string  `json:"place_name"`
+	ClearCoords bool     `json:"clear_coordinates"`
+	Tags        []string `json:"tags"`
+	// Photos 或 Images 不为空时替换事件的全部图片
+	Photos     []EventImageUpload     `json:"photos"`
+	Images     []string               `json:"images"`
+	Status     *model.EventStatus     `json:"status"`
+	PublishAt  *time.Time             `json:"publish_at"`
+	Visibility *model.EventVisibility `json:"visibility"`
+	VisibleTo  []uuid.UUID            `json:"visible_to"`
 }
 
 type QueryEventsRequest struct {
-	SpaceID   uuid.UUID  `json:"space_id"`
-	StartDate *time.Time `json:"start_date"`
-	EndDate   *time.Time `json:"end_date"`
-	Limit     int        `json:"limit"`
-	Offset    int        `json:"offset"`
+	SpaceID      uuid.UUID  `json:"space_id"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	Cursor       string     `json:"cursor"`
+	Limit        int        `json:"limit"`
+	IncludeTotal bool       `json:"include_total"`
+}
+
+type EventPage struct {
+	pagination.Page[model.Event]
+	Total *int64 `json:"total,omitempty"`
+}
+
+type trashCursor struct {
+	DeletedAt time.Time `json:"d"`
+	ID        uuid.UUID `json:"id"`
+}
+
+type TrashedEventResponse struct {
+	*model.Event
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+func (r TrashedEventResponse) MarshalJSON() ([]byte, error) {
+	return model.MarshalEventWith(r.Event, struct {
+		DeletedAt time.Time `json:"deleted_at"`
+		PurgeAt   time.Time `json:"purge_at"`
+	}{r.DeletedAt, r.PurgeAt})
+}
+
+// VersionConflictError 事件已被他人修改，CurrentVersion 为服务器上的最新版本
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("事件已被其他成员修改（当前版本 %d），请刷新后重试", e.CurrentVersion)
 }
 
 // CreateEvent 创建事件
@@ -69,24 +159,95 @@ func (s *EventService) CreateEvent(req *CreateEventRequest, userID uuid.UUID) (*
 		return nil, errors.New("您不在该空间中，无法创建事件")
 	}
 
+	if err := s.ensureSpaceWritable(req.SpaceID); err != nil {
+		return nil, err
+	}
+
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
+
+	eventDate, err := localtime.ParseDate(req.EventDate)
+	if err != nil {
+		return nil, err
+	}
+	eventTime, err := optionalClock(req.EventTime)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := optionalDate(req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := optionalClock(req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateEventSpan(eventDate, eventTime, endDate, endTime); err != nil {
+		return nil, err
+	}
+
+	timeZone, err := s.resolveTimeZone(userID, req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.normalizeTags(req.SpaceID, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := buildEventImages(req.Photos, req.Images)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建事件
 	event := &model.Event{
 		SpaceID:     req.SpaceID,
 		UserID:      userID,
-		EventDate:   req.EventDate,
-		EventTime:   req.EventTime,
+		EventDate:   eventDate,
+		EventTime:   eventTime,
+		EndDate:     endDate,
+		EndTime:     endTime,
+		TimeZone:    timeZone,
 		Title:       req.Title,
 		Content:     req.Content,
 		Description: req.Description,
 		Location:    req.Location,
-		Tags:        req.Tags,
-		ImageURLs:   req.Images,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		PlaceName:   req.PlaceName,
+		Tags:        tags,
+		Images:      images,
+		Version:     1,
+	}
+
+	status := req.Status
+	if status == "" {
+		status = model.EventStatusPublished
+	}
+	if err := applyEventStatus(event, status, req.PublishAt); err != nil {
+		return nil, err
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = model.EventVisibilityEveryone
+	}
+	if err := s.applyVisibility(event, visibility, req.VisibleTo); err != nil {
+		return nil, err
 	}
 
 	if err := s.eventRepo.Create(event); err != nil {
 		return nil, err
 	}
 
+	s.registerTags(event)
+	s.recordActivity(event, userID, model.ActivityEventCreated)
+	s.notifyMentions(event, userID)
+	InvalidateSpaceStats(event.SpaceID)
+
 	// 重新查询完整的事件数据
 	return s.eventRepo.FindByID(event.ID)
 }
@@ -98,20 +259,19 @@ func (s *EventService) GetEventByID(eventID, userID uuid.UUID) (*model.Event, er
 		return nil, err
 	}
 
-	// 检查用户是否有权限查看
-	isMember, err := s.spaceRepo.IsUserInSpace(event.SpaceID, userID)
-	if err != nil {
+	if err := s.checkCanView(event, userID); err != nil {
 		return nil, err
 	}
-	if !isMember {
-		return nil, errors.New("无权访问该事件")
-	}
 
-	return event, nil
+	events := []model.Event{*event}
+	if err := s.eventRepo.AttachReactions(events, userID); err != nil {
+		return nil, err
+	}
+	return &events[0], nil
 }
 
-// GetEventsBySpace 获取空间的事件列表
-func (s *EventService) GetEventsBySpace(req *QueryEventsRequest, userID uuid.UUID) ([]model.Event, error) {
+// GetEventsBySpace 获取空间的事件列表（游标分页）
+func (s *EventService) GetEventsBySpace(req *QueryEventsRequest, userID uuid.UUID) (*EventPage, error) {
 	// 检查用户是否在该空间
 	isMember, err := s.spaceRepo.IsUserInSpace(req.SpaceID, userID)
 	if err != nil {
@@ -121,17 +281,31 @@ func (s *EventService) GetEventsBySpace(req *QueryEventsRequest, userID uuid.UUI
 		return nil, errors.New("无权访问该空间")
 	}
 
-	// 如果指定了日期范围，使用日期范围查询
-	if req.StartDate != nil && req.EndDate != nil {
-		return s.eventRepo.FindByDateRange(req.SpaceID, *req.StartDate, *req.EndDate)
+	after, err := decodeEventCursor(req.Cursor)
+	if err != nil {
+		return nil, err
 	}
 
-	// 否则使用分页查询
-	limit := req.Limit
-	if limit == 0 {
-		limit = 50 // 默认返回 50 条
+	limit := pagination.ClampLimit(req.Limit)
+	events, err := s.eventRepo.FindByDateRange(req.SpaceID, userID, req.StartDate, req.EndDate, after, limit+1)
+	if err != nil {
+		return nil, err
 	}
-	return s.eventRepo.FindBySpaceID(req.SpaceID, limit, req.Offset)
+
+	page, err := buildEventPage(events, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IncludeTotal {
+		total, err := s.eventRepo.CountByDateRange(req.SpaceID, userID, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
 }
 
 // UpdateEvent 更新事件
@@ -146,12 +320,54 @@ func (s *EventService) UpdateEvent(eventID, userID uuid.UUID, req *UpdateEventRe
 		return nil, errors.New("只有创建者可以修改事件")
 	}
 
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return nil, err
+	}
+
+	if req.Version == nil {
+		return nil, errors.New("缺少版本号，请通过 If-Match 或 version 字段提供")
+	}
+	if *req.Version != event.Version {
+		return nil, &VersionConflictError{CurrentVersion: event.Version}
+	}
+
+	before := event.Snapshot()
+	wasPublic := event.IsPublic(time.Now())
+
 	// 更新字段
 	if req.EventDate != nil {
-		event.EventDate = *req.EventDate
+		if event.EventDate, err = localtime.ParseDate(*req.EventDate); err != nil {
+			return nil, err
+		}
 	}
 	if req.EventTime != nil {
-		event.EventTime = req.EventTime
+		if event.EventTime, err = optionalClock(req.EventTime); err != nil {
+			return nil, err
+		}
+	}
+	if req.ClearEnd {
+		event.EndDate = nil
+		event.EndTime = nil
+	} else {
+		if req.EndDate != nil {
+			if event.EndDate, err = optionalDate(req.EndDate); err != nil {
+				return nil, err
+			}
+		}
+		if req.EndTime != nil {
+			if event.EndTime, err = optionalClock(req.EndTime); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.TimeZone != nil {
+		if _, err := localtime.LoadZone(*req.TimeZone); err != nil {
+			return nil, err
+		}
+		event.TimeZone = *req.TimeZone
+	}
+	if err := validateEventSpan(event.EventDate, event.EventTime, event.EndDate, event.EndTime); err != nil {
+		return nil, err
 	}
 	if req.Title != nil {
 		event.Title = *req.Title
@@ -165,20 +381,198 @@ func (s *EventService) UpdateEvent(eventID, userID uuid.UUID, req *UpdateEventRe
 	if req.Location != nil {
 		event.Location = *req.Location
 	}
+	if req.ClearCoords {
+		event.Latitude = nil
+		event.Longitude = nil
+	} else if req.Latitude != nil || req.Longitude != nil {
+		if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+			return nil, err
+		}
+		event.Latitude = req.Latitude
+		event.Longitude = req.Longitude
+	}
+	if req.PlaceName != nil {
+		event.PlaceName = *req.PlaceName
+	}
 	if req.Tags != nil {
-		event.Tags = req.Tags
+		tags, err := s.normalizeTags(event.SpaceID, req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		event.Tags = tags
 	}
-	if req.Images != nil {
-		event.ImageURLs = req.Images
+	if req.Status != nil || req.PublishAt != nil {
+		status := event.Status
+		if req.Status != nil {
+			status = *req.Status
+		}
+		publishAt := event.PublishAt
+		if req.PublishAt != nil {
+			publishAt = req.PublishAt
+		}
+		if err := applyEventStatus(event, status, publishAt); err != nil {
+			return nil, err
+		}
+	}
+	if req.Visibility != nil || req.VisibleTo != nil {
+		visibility := event.Visibility
+		if req.Visibility != nil {
+			visibility = *req.Visibility
+		}
+		visibleTo := event.VisibleTo
+		if req.VisibleTo != nil {
+			visibleTo = req.VisibleTo
+		}
+		if err := s.applyVisibility(event, visibility, visibleTo); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.eventRepo.Update(event); err != nil {
+	after := event.Snapshot()
+	replaceImages := req.Photos != nil || req.Images != nil
+	var images []model.EventImage
+	if replaceImages {
+		if images, err = buildEventImages(req.Photos, req.Images); err != nil {
+			return nil, err
+		}
+		after.Images = make([]string, 0, len(images))
+		for _, image := range images {
+			after.Images = append(after.Images, image.ImageURL)
+		}
+	}
+
+	if err := s.saveWithRevision(event, userID, &before, &after); err != nil {
 		return nil, err
 	}
 
+	if replaceImages {
+		removed, err := s.eventRepo.ReplaceImages(event.ID, images)
+		if err != nil {
+			return nil, err
+		}
+		s.removeUnreferencedFiles(context.Background(), removed)
+	}
+
+	s.registerTags(event)
+	// 草稿发布或私密事件公开后，对空间成员而言是一条新事件
+	if wasPublic {
+		s.recordActivity(event, userID, model.ActivityEventUpdated)
+	} else {
+		s.recordActivity(event, userID, model.ActivityEventCreated)
+	}
+	// 发布状态或可见范围变化后可能有新成员能看到提及，已通知过的成员不会重复通知
+	accessChanged := req.Status != nil || req.PublishAt != nil || req.Visibility != nil || req.VisibleTo != nil
+	if req.Content != nil || accessChanged {
+		s.notifyMentions(event, userID)
+	}
+	InvalidateSpaceStats(event.SpaceID)
+
+	return s.eventRepo.FindByID(eventID)
+}
+
+// GetEventRevisions 获取事件的修订历史（空间成员可查看）
+func (s *EventService) GetEventRevisions(eventID, userID uuid.UUID) ([]model.EventRevision, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("事件不存在")
+	}
+
+	if err := s.checkCanView(event, userID); err != nil {
+		return nil, err
+	}
+
+	return s.revisionRepo.FindByEventID(eventID)
+}
+
+// RestoreEventRevision 将事件恢复到某次修改之前的内容，恢复本身也会生成一条修订记录
+func (s *EventService) RestoreEventRevision(eventID uuid.UUID, revision int, userID uuid.UUID) (*model.Event, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("事件不存在")
+	}
+
+	if event.UserID != userID {
+		return nil, errors.New("只有创建者可以修改事件")
+	}
+
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revisionRepo.FindByRevision(eventID, revision)
+	if err != nil {
+		return nil, errors.New("修订记录不存在")
+	}
+
+	before := event.Snapshot()
+	event.ApplySnapshot(&rev.Snapshot)
+	if event.Tags, err = s.normalizeTags(event.SpaceID, event.Tags); err != nil {
+		return nil, err
+	}
+	after := event.Snapshot()
+
+	if err := s.saveWithRevision(event, userID, &before, &after); err != nil {
+		return nil, err
+	}
+
+	s.registerTags(event)
+	s.recordActivity(event, userID, model.ActivityEventUpdated)
+	s.notifyMentions(event, userID)
+	InvalidateSpaceStats(event.SpaceID)
+
 	return s.eventRepo.FindByID(eventID)
 }
 
+// PruneRevisions 按保留策略清理修订记录，由定时任务调用
+func (s *EventService) PruneRevisions(ctx context.Context) error {
+	cfg := config.AppConfig.Revision
+	if cfg.RetentionDays > 0 {
+		before := time.Now().Add(-time.Duration(cfg.RetentionDays) * 24 * time.Hour)
+		count, err := s.revisionRepo.DeleteBefore(before)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Printf("已清理 %d 条过期的事件修订记录", count)
+		}
+	}
+	if cfg.MaxPerEvent > 0 {
+		count, err := s.revisionRepo.DeleteExcess(cfg.MaxPerEvent)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Printf("已清理 %d 条超出数量限制的事件修订记录", count)
+		}
+	}
+	return nil
+}
+
+// saveWithRevision 保存事件，内容有变化时同时记录修订
+func (s *EventService) saveWithRevision(event *model.Event, editorID uuid.UUID, before, after *model.EventSnapshot) error {
+	var err error
+	changes := model.DiffSnapshots(before, after)
+	if len(changes) == 0 || s.revisionRepo == nil {
+		err = s.eventRepo.Update(event)
+	} else {
+		err = s.eventRepo.UpdateWithRevision(event, &model.EventRevision{
+			EditorID: editorID,
+			Changes:  changes,
+			Snapshot: *before,
+		})
+	}
+
+	// 读取之后被他人修改过，返回最新版本号
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, findErr := s.eventRepo.FindByID(event.ID)
+		if findErr != nil {
+			return findErr
+		}
+		return &VersionConflictError{CurrentVersion: current.Version}
+	}
+	return err
+}
+
 // DeleteEvent 删除事件
 func (s *EventService) DeleteEvent(eventID, userID uuid.UUID) error {
 	event, err := s.eventRepo.FindByID(eventID)
@@ -186,23 +580,614 @@ func (s *EventService) DeleteEvent(eventID, userID uuid.UUID) error {
 		return err
 	}
 
-	// 检查权限（只有创建者或空间 owner 可以删除）
-	if event.UserID != userID {
-		// 检查是否是空间 owner
-		space, err := s.spaceRepo.FindByID(event.SpaceID)
+	// 检查权限（只有创建者或空间 owner 可以删除，owner 看不到的草稿和私密事件按不存在处理）
+	if !event.IsVisibleTo(userID, time.Now()) {
+		return errors.New("事件不存在")
+	}
+	if err := s.checkCanDelete(event, userID); err != nil {
+		return err
+	}
+
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return err
+	}
+
+	// 软删除，事件进入回收站
+	if err := s.eventRepo.Delete(eventID); err != nil {
+		return err
+	}
+
+	s.recordActivity(event, userID, model.ActivityEventDeleted)
+	InvalidateSpaceStats(event.SpaceID)
+	return nil
+}
+
+// GetTrashedEvents 获取空间回收站中的事件
+func (s *EventService) GetTrashedEvents(spaceID, userID uuid.UUID, cursor string, limit int) (*pagination.Page[TrashedEventResponse], error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	var beforeTime *time.Time
+	var beforeID *uuid.UUID
+	if cursor != "" {
+		var c trashCursor
+		if err := pagination.DecodeCursor(cursor, &c); err != nil {
+			return nil, err
+		}
+		beforeTime, beforeID = &c.DeletedAt, &c.ID
+	}
+
+	limit = pagination.ClampLimit(limit)
+	events, err := s.eventRepo.FindTrashedBySpaceID(spaceID, userID, beforeTime, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page[TrashedEventResponse]{}
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[len(events)-1]
+		next, err := pagination.EncodeCursor(trashCursor{DeletedAt: last.DeletedAt.Time, ID: last.ID})
 		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+		page.HasMore = true
+	}
+
+	retention := eventTrashRetention()
+	page.Items = make([]TrashedEventResponse, 0, len(events))
+	for i := range events {
+		deletedAt := events[i].DeletedAt.Time
+		page.Items = append(page.Items, TrashedEventResponse{
+			Event:     &events[i],
+			DeletedAt: deletedAt,
+			PurgeAt:   deletedAt.Add(retention),
+		})
+	}
+	return page, nil
+}
+
+// RestoreEvent 从回收站恢复事件（创建者或空间 owner）
+func (s *EventService) RestoreEvent(eventID, userID uuid.UUID) (*model.Event, error) {
+	event, err := s.eventRepo.FindTrashedByID(eventID)
+	if err != nil || !event.IsVisibleTo(userID, time.Now()) {
+		return nil, errors.New("回收站中没有该事件")
+	}
+
+	isMember, err := s.spaceRepo.IsUserInSpace(event.SpaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	if err := s.checkCanDelete(event, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.Restore(eventID); err != nil {
+		return nil, err
+	}
+
+	s.recordActivity(event, userID, model.ActivityEventRestored)
+	InvalidateSpaceStats(event.SpaceID)
+
+	return s.eventRepo.FindByID(eventID)
+}
+
+// PermanentlyDeleteEvent 彻底删除回收站中的事件，并清理不再被引用的图片文件
+func (s *EventService) PermanentlyDeleteEvent(ctx context.Context, eventID, userID uuid.UUID) error {
+	event, err := s.eventRepo.FindTrashedByID(eventID)
+	if err != nil || !event.IsVisibleTo(userID, time.Now()) {
+		return errors.New("回收站中没有该事件")
+	}
+
+	isMember, err := s.spaceRepo.IsUserInSpace(event.SpaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("无权访问该空间")
+	}
+
+	if err := s.checkCanDelete(event, userID); err != nil {
+		return err
+	}
+
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return err
+	}
+
+	return s.purgeEvent(ctx, event)
+}
+
+// PublishDueEvents 将发布时间已到的定时发布事件（时间胶囊）转为已发布，由定时任务调用。
+// 创建时因尚未公开而跳过的标签登记、空间动态和提及通知在这里补上
+func (s *EventService) PublishDueEvents(ctx context.Context) error {
+	now := time.Now()
+	events, err := s.eventRepo.FindDueScheduled(now)
+	if err != nil {
+		return err
+	}
+
+	published := 0
+	for i := range events {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
-		if space.OwnerID != userID {
-			return errors.New("只有创建者或空间创建者可以删除事件")
+		event := &events[i]
+
+		ok, err := s.eventRepo.MarkPublished(event.ID, now)
+		if err != nil {
+			log.Printf("发布定时事件 %s 失败: %v", event.ID, err)
+			continue
+		}
+		if !ok {
+			continue
 		}
+		event.Status = model.EventStatusPublished
+
+		s.registerTags(event)
+		s.recordActivity(event, event.UserID, model.ActivityEventCreated)
+		s.notifyMentions(event, event.UserID)
+		InvalidateSpaceStats(event.SpaceID)
+		published++
+	}
+
+	if published > 0 {
+		log.Printf("已发布 %d 个定时事件", published)
+	}
+	return nil
+}
+
+// PurgeExpiredEvents 彻底删除超过保留期的回收站事件，由定时任务调用
+func (s *EventService) PurgeExpiredEvents(ctx context.Context) error {
+	events, err := s.eventRepo.FindTrashedBefore(time.Now().Add(-eventTrashRetention()))
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		if err := s.purgeEvent(ctx, &events[i]); err != nil {
+			// 单个事件失败不影响其他事件，下次任务会重试
+			log.Printf("清理事件 %s 失败: %v", events[i].ID, err)
+			continue
+		}
+	}
+	if len(events) > 0 {
+		log.Printf("已彻底清理 %d 个回收站事件", len(events))
+	}
+
+	return nil
+}
+
+func (s *EventService) purgeEvent(ctx context.Context, event *model.Event) error {
+	if err := s.eventRepo.Purge(event.ID); err != nil {
+		return err
+	}
+	s.removeUnreferencedFiles(ctx, event.Images)
+	return nil
+}
+
+// checkCanDelete 只有创建者或空间 owner 可以删除、恢复事件
+func (s *EventService) checkCanDelete(event *model.Event, userID uuid.UUID) error {
+	if event.UserID == userID {
+		return nil
+	}
+
+	space, err := s.spaceRepo.FindByID(event.SpaceID)
+	if err != nil {
+		return err
+	}
+	if space.OwnerID != userID {
+		return errors.New("只有创建者或空间创建者可以删除事件")
+	}
+	return nil
+}
+
+// ReorderEventImages 调整事件图片顺序，imageIDs 需包含事件的全部图片
+func (s *EventService) ReorderEventImages(eventID, userID uuid.UUID, req *ReorderImagesRequest) (*model.Event, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("事件不存在")
+	}
+
+	if event.UserID != userID {
+		return nil, errors.New("只有创建者可以修改事件")
+	}
+
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return nil, err
+	}
+
+	if len(req.ImageIDs) != len(event.Images) {
+		return nil, errors.New("排序需要包含事件的全部图片")
+	}
+	current := make(map[uuid.UUID]bool, len(event.Images))
+	for _, image := range event.Images {
+		current[image.ID] = true
+	}
+	for _, id := range req.ImageIDs {
+		if !current[id] {
+			return nil, errors.New("图片不属于该事件")
+		}
+		delete(current, id)
+	}
+
+	if err := s.eventRepo.ReorderImages(eventID, req.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	InvalidateSpaceStats(event.SpaceID)
+	return s.eventRepo.FindByID(eventID)
+}
+
+// DeleteEventImage 删除事件中的一张图片，没有其他事件引用时同时删除存储中的原图和缩略图
+func (s *EventService) DeleteEventImage(ctx context.Context, eventID, imageID, userID uuid.UUID) (*model.Event, error) {
+	event, image, err := s.findEditableImage(eventID, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.DeleteImage(event.ID, image.ID); err != nil {
+		return nil, err
+	}
+	s.removeUnreferencedFiles(ctx, []model.EventImage{*image})
+
+	InvalidateSpaceStats(event.SpaceID)
+	return s.eventRepo.FindByID(eventID)
+}
+
+// SetCoverImage 将图片设为封面（排到第一张）
+func (s *EventService) SetCoverImage(eventID, imageID, userID uuid.UUID) (*model.Event, error) {
+	event, image, err := s.findEditableImage(eventID, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := []uuid.UUID{image.ID}
+	for _, other := range event.Images {
+		if other.ID != image.ID {
+			order = append(order, other.ID)
+		}
+	}
+	if err := s.eventRepo.ReorderImages(event.ID, order); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.FindByID(eventID)
+}
+
+// findEditableImage 查询事件及其图片，并检查当前用户是否可以修改
+func (s *EventService) findEditableImage(eventID, imageID, userID uuid.UUID) (*model.Event, *model.EventImage, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, nil, errors.New("事件不存在")
+	}
+
+	image, err := s.eventRepo.FindImageByID(imageID)
+	if err != nil || image.EventID != event.ID {
+		return nil, nil, errors.New("图片不存在")
+	}
+
+	if event.UserID != userID {
+		return nil, nil, errors.New("只有创建者可以修改事件")
+	}
+
+	if err := s.ensureSpaceWritable(event.SpaceID); err != nil {
+		return nil, nil, err
 	}
 
-	return s.eventRepo.Delete(eventID)
+	return event, image, nil
+}
+
+// removeUnreferencedFiles 删除不再被任何图片记录引用的存储文件，失败只记录日志
+func (s *EventService) removeUnreferencedFiles(ctx context.Context, images []model.EventImage) {
+	if s.storage == nil {
+		return
+	}
+
+	for _, image := range images {
+		for _, url := range []string{image.ImageURL, image.ThumbnailURL} {
+			if url == "" {
+				continue
+			}
+			count, err := s.eventRepo.CountImageReferences(url)
+			if err != nil {
+				log.Printf("检查图片引用失败: %v", err)
+				continue
+			}
+			if count > 0 {
+				continue
+			}
+			if err := s.storage.DeleteFile(ctx, storage.GetObjectNameFromURL(url)); err != nil {
+				log.Printf("删除图片文件失败: %v", err)
+			}
+		}
+	}
+}
+
+// ensureSpaceWritable 已归档的空间不允许写入
+func (s *EventService) ensureSpaceWritable(spaceID uuid.UUID) error {
+	archived, err := s.spaceRepo.IsArchived(spaceID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return ErrSpaceArchived
+	}
+	return nil
+}
+
+// buildEventImages 将请求中的图片转换为图片记录，按 sort_order 排序后重新编号
+func buildEventImages(photos []EventImageUpload, urls []string) ([]model.EventImage, error) {
+	if photos == nil {
+		photos = make([]EventImageUpload, 0, len(urls))
+		for i, url := range urls {
+			photos = append(photos, EventImageUpload{
+				ImageURL:     url,
+				ThumbnailURL: storage.ThumbnailURLFor(url),
+				SortOrder:    i,
+			})
+		}
+	}
+
+	sorted := make([]EventImageUpload, len(photos))
+	copy(sorted, photos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].SortOrder < sorted[j].SortOrder
+	})
+
+	images := make([]model.EventImage, 0, len(sorted))
+	seen := make(map[string]bool, len(sorted))
+	for _, photo := range sorted {
+		if photo.ImageURL == "" {
+			return nil, errors.New("图片地址不能为空")
+		}
+		if seen[photo.ImageURL] {
+			continue
+		}
+		seen[photo.ImageURL] = true
+
+		thumbnail := photo.ThumbnailURL
+		if thumbnail == "" {
+			thumbnail = storage.ThumbnailURLFor(photo.ImageURL)
+		}
+		images = append(images, model.EventImage{
+			ImageURL:     photo.ImageURL,
+			ThumbnailURL: thumbnail,
+			Width:        photo.Width,
+			Height:       photo.Height,
+			SortOrder:    len(images),
+		})
+	}
+	return images, nil
+}
+
+// resolveTimeZone 校验请求中的时区，未指定时使用用户资料中的时区
+func (s *EventService) resolveTimeZone(userID uuid.UUID, timeZone string) (string, error) {
+	if timeZone != "" {
+		if _, err := localtime.LoadZone(timeZone); err != nil {
+			return "", err
+		}
+		return timeZone, nil
+	}
+
+	if s.userRepo != nil {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return "", err
+		}
+		if user.TimeZone != "" {
+			return user.TimeZone, nil
+		}
+	}
+	return localtime.DefaultZone, nil
+}
+
+// optionalDate 解析可选的本地日期，空字符串表示不设置
+func optionalDate(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	date, err := localtime.ParseDate(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// optionalClock 解析可选的本地时间，空字符串表示不设置
+func optionalClock(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	clock, err := localtime.ParseClock(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &clock, nil
+}
+
+// validateEventSpan 结束日期不能早于开始日期；同一天结束时结束时间不能早于开始时间
+func validateEventSpan(eventDate time.Time, eventTime, endDate, endTime *time.Time) error {
+	start := eventDate.Format(localtime.DateLayout)
+	end := start
+	if endDate != nil {
+		end = *localtime.FormatDate(endDate)
+	}
+	if end < start {
+		return errors.New("结束日期不能早于开始日期")
+	}
+	if end == start && eventTime != nil && endTime != nil && *localtime.FormatClock(endTime) < *localtime.FormatClock(eventTime) {
+		return errors.New("结束时间不能早于开始时间")
+	}
+	return nil
+}
+
+// validateCoordinates 经纬度需同时提供且在合法范围内
+func validateCoordinates(latitude, longitude *float64) error {
+	if latitude == nil && longitude == nil {
+		return nil
+	}
+	if latitude == nil || longitude == nil {
+		return errors.New("经度和纬度需要同时提供")
+	}
+	if !validator.IsValidCoordinate(*latitude, *longitude) {
+		return errors.New("经纬度超出范围")
+	}
+	return nil
+}
+
+// normalizeTags 按空间标签登记规范化事件标签
+func (s *EventService) normalizeTags(spaceID uuid.UUID, tags []string) ([]string, error) {
+	if s.tagService == nil {
+		return tags, nil
+	}
+	return s.tagService.NormalizeTags(spaceID, tags)
+}
+
+// registerTags 登记公开事件中的新标签，失败只记录日志；标签列表对全体成员可见，私密事件的标签不登记
+func (s *EventService) registerTags(event *model.Event) {
+	if s.tagService == nil || len(event.Tags) == 0 || !event.IsPublic(time.Now()) {
+		return
+	}
+	if err := s.tagService.RegisterTags(event.SpaceID, event.Tags); err != nil {
+		log.Printf("登记事件标签失败: %v", err)
+	}
+}
+
+// recordActivity 记录空间动态，动态对全体成员可见，只记录公开的事件
+func (s *EventService) recordActivity(event *model.Event, actorID uuid.UUID, activityType model.ActivityType) {
+	if s.activityService != nil && event.IsPublic(time.Now()) {
+		s.activityService.Record(event.SpaceID, actorID, activityType, &event.ID, event.Title)
+	}
+}
+
+func (s *EventService) notifyMentions(event *model.Event, actorID uuid.UUID) {
+	if s.mentionService != nil {
+		s.mentionService.NotifyEventMentions(event, actorID)
+	}
+}
+
+// checkCanView 检查用户是空间成员且能看到该事件，看不到的草稿按不存在处理
+func (s *EventService) checkCanView(event *model.Event, userID uuid.UUID) error {
+	isMember, err := s.spaceRepo.IsUserInSpace(event.SpaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("无权访问该事件")
+	}
+	if !event.IsVisibleTo(userID, time.Now()) {
+		return errors.New("事件不存在")
+	}
+	return nil
+}
+
+// applyEventStatus 校验并设置发布状态，只有定时发布保留 PublishAt
+func applyEventStatus(event *model.Event, status model.EventStatus, publishAt *time.Time) error {
+	if !status.IsValid() {
+		return errors.New("无效的发布状态")
+	}
+	if status != model.EventStatusScheduled {
+		event.Status = status
+		event.PublishAt = nil
+		return nil
+	}
+
+	if publishAt == nil {
+		return errors.New("定时发布需要指定发布时间")
+	}
+	if !publishAt.After(time.Now()) {
+		return errors.New("定时发布时间必须晚于当前时间")
+	}
+	event.Status = status
+	event.PublishAt = publishAt
+	return nil
+}
+
+// applyVisibility 校验并设置可见范围，members 范围的成员必须属于事件所在空间
+func (s *EventService) applyVisibility(event *model.Event, visibility model.EventVisibility, visibleTo []uuid.UUID) error {
+	if !visibility.IsValid() {
+		return errors.New("无效的可见范围")
+	}
+	if visibility != model.EventVisibilityMembers {
+		event.Visibility = visibility
+		event.VisibleTo = nil
+		return nil
+	}
+
+	members, err := s.spaceRepo.GetMembers(event.SpaceID)
+	if err != nil {
+		return err
+	}
+	inSpace := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		inSpace[member.UserID] = true
+	}
+
+	// 作者总能看到自己的事件，不需要出现在列表中
+	seen := make(map[uuid.UUID]bool, len(visibleTo))
+	ids := make([]uuid.UUID, 0, len(visibleTo))
+	for _, id := range visibleTo {
+		if id == event.UserID || seen[id] {
+			continue
+		}
+		if !inSpace[id] {
+			return errors.New("可见成员必须是空间成员")
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return errors.New("请选择可以查看该事件的成员")
+	}
+
+	event.Visibility = visibility
+	event.VisibleTo = ids
+	return nil
+}
+
+// decodeEventCursor 解析事件列表游标，空字符串表示第一页
+func decodeEventCursor(cursor string) (*repository.EventCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	var after repository.EventCursor
+	if err := pagination.DecodeCursor(cursor, &after); err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// buildEventPage 根据多查询的一条判断是否还有下一页，events 需按 limit+1 查询
+func buildEventPage(events []model.Event, limit int) (*EventPage, error) {
+	page := &EventPage{}
+	if len(events) > limit {
+		events = events[:limit]
+		next, err := pagination.EncodeCursor(repository.NewEventCursor(&events[len(events)-1]))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+		page.HasMore = true
+	}
+	page.Items = events
+	return page, nil
 }
 
-// DeleteEventImage 删除事件图片
-func (s *EventService) DeleteEventImage(imageID, userID uuid.UUID) error {
-	// TODO: 需要先查询图片所属的事件，检查权限
-	return s.eventRepo.DeleteImage(imageID)
+func eventTrashRetention() time.Duration {
+	return time.Duration(config.AppConfig.Trash.EventRetentionDays) * 24 * time.Hour
 }
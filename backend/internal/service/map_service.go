@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/validator"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+const (
+	maxMapZoom = 20
+	// 每个地图瓦片划分的网格数，约等于 64px 聚合一次（瓦片为 256px）
+	mapCellsPerTile = 4
+	maxMapClusters  = 500
+)
+
+type MapService struct {
+	eventRepo *repository.EventRepository
+	spaceRepo *repository.SpaceRepository
+}
+
+func NewMapService(eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository) *MapService {
+	return &MapService{
+		eventRepo: eventRepo,
+		spaceRepo: spaceRepo,
+	}
+}
+
+type EventMapResponse struct {
+	Zoom     int                     `json:"zoom"`
+	Clusters []repository.MapCluster `json:"clusters"`
+}
+
+// GetEventMap 获取范围内事件的聚合点，bbox 格式为 "minLng,minLat,maxLng,maxLat"，为空表示全球
+func (s *MapService) GetEventMap(spaceID, userID uuid.UUID, bbox string, zoom int) (*EventMapResponse, error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	if zoom < 0 || zoom > maxMapZoom {
+		return nil, errors.New("缩放级别应在 0 到 20 之间")
+	}
+
+	box, err := parseBoundingBox(bbox)
+	if err != nil {
+		return nil, err
+	}
+
+	// 缩放级别每加一级，网格边长减半
+	cellSize := 360 / (math.Pow(2, float64(zoom)) * mapCellsPerTile)
+
+	clusters, err := s.eventRepo.MapClusters(spaceID, userID, box, cellSize, maxMapClusters)
+	if err != nil {
+		return nil, err
+	}
+	if clusters == nil {
+		clusters = []repository.MapCluster{}
+	}
+
+	return &EventMapResponse{Zoom: zoom, Clusters: clusters}, nil
+}
+
+func parseBoundingBox(bbox string) (repository.BoundingBox, error) {
+	if bbox == "" {
+		return repository.BoundingBox{MinLng: -180, MinLat: -90, MaxLng: 180, MaxLat: 90}, nil
+	}
+
+	parts := strings.Split(bbox, ",")
+	if len(parts) != 4 {
+		return repository.BoundingBox{}, errors.New("bbox 格式应为 minLng,minLat,maxLng,maxLat")
+	}
+	values := make([]float64, 4)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return repository.BoundingBox{}, errors.New("bbox 格式应为 minLng,minLat,maxLng,maxLat")
+		}
+		values[i] = value
+	}
+
+	box := repository.BoundingBox{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+	if !validator.IsValidCoordinate(box.MinLat, box.MinLng) || !validator.IsValidCoordinate(box.MaxLat, box.MaxLng) {
+		return repository.BoundingBox{}, errors.New("经纬度超出范围")
+	}
+	if box.MinLat > box.MaxLat {
+		return repository.BoundingBox{}, errors.New("bbox 的最小纬度不能大于最大纬度")
+	}
+	return box, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/mention"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+type MentionService struct {
+	mentionRepo *repository.MentionRepository
+	spaceRepo   *repository.SpaceRepository
+	emailSender EmailSender
+}
+
+func NewMentionService(mentionRepo *repository.MentionRepository, spaceRepo *repository.SpaceRepository, emailSender EmailSender) *MentionService {
+	return &MentionService{
+		mentionRepo: mentionRepo,
+		spaceRepo:   spaceRepo,
+		emailSender: emailSender,
+	}
+}
+
+type mentionCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+type MentionFeedResponse struct {
+	pagination.Page[model.Mention]
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// NotifyEventMentions 解析事件内容中的 @ 提及，只通知此前未被提及的成员
+func (s *MentionService) NotifyEventMentions(event *model.Event, actorID uuid.UUID) {
+	s.notify(event, nil, actorID, event.Content)
+}
+
+// NotifyCommentMentions 解析评论中的 @ 提及
+func (s *MentionService) NotifyCommentMentions(event *model.Event, comment *model.EventComment) {
+	s.notify(event, &comment.ID, comment.UserID, comment.Content)
+}
+
+// notify 记录提及并发送邮件，失败只记录日志，不影响主流程
+// 草稿和尚未到发布时间的事件不发送提及通知，定时发布的事件到期后由 PublishDueEvents 处理
+func (s *MentionService) notify(event *model.Event, commentID *uuid.UUID, actorID uuid.UUID, text string) {
+	if !strings.Contains(text, "@") || !event.IsPublished(time.Now()) {
+		return
+	}
+
+	members, err := s.spaceRepo.GetMembers(event.SpaceID)
+	if err != nil {
+		log.Printf("查询空间成员失败: %v", err)
+		return
+	}
+
+	// 成员可以用空间内昵称或用户名被提及
+	byName := make(map[string]*model.SpaceMember)
+	names := make([]string, 0, len(members)*2)
+	var actorName string
+	now := time.Now()
+	for i := range members {
+		member := &members[i]
+		if member.UserID == actorID {
+			actorName = displayName(member)
+			continue
+		}
+		// 看不到事件的成员不能被提及
+		if !event.IsVisibleTo(member.UserID, now) {
+			continue
+		}
+		for _, name := range []string{member.Nickname, member.User.Username} {
+			key := strings.ToLower(name)
+			if name == "" || byName[key] != nil {
+				continue
+			}
+			byName[key] = member
+			names = append(names, name)
+		}
+	}
+
+	matched := mention.Match(text, names)
+	if len(matched) == 0 {
+		return
+	}
+
+	existing, err := s.mentionRepo.FindMentionedUserIDs(event.ID, commentID)
+	if err != nil {
+		log.Printf("查询提及记录失败: %v", err)
+		return
+	}
+	notified := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		notified[id] = true
+	}
+
+	var mentions []model.Mention
+	var recipients []*model.SpaceMember
+	for _, name := range matched {
+		member := byName[strings.ToLower(name)]
+		if notified[member.UserID] {
+			continue
+		}
+		notified[member.UserID] = true
+		mentions = append(mentions, model.Mention{
+			UserID:    member.UserID,
+			ActorID:   actorID,
+			SpaceID:   event.SpaceID,
+			EventID:   event.ID,
+			CommentID: commentID,
+		})
+		recipients = append(recipients, member)
+	}
+
+	if err := s.mentionRepo.CreateBatch(mentions); err != nil {
+		log.Printf("记录提及失败: %v", err)
+		return
+	}
+
+	if s.emailSender == nil || len(recipients) == 0 {
+		return
+	}
+
+	var spaceName string
+	if space, err := s.spaceRepo.FindByID(event.SpaceID); err == nil {
+		spaceName = space.Name
+	}
+	excerpt := truncateRunes(text, 200)
+
+	go func() {
+		for _, member := range recipients {
+			data := &MentionEmail{
+				ActorName:  actorName,
+				SpaceName:  spaceName,
+				SpaceID:    event.SpaceID,
+				EventID:    event.ID,
+				EventTitle: event.Title,
+				Excerpt:    excerpt,
+			}
+			if err := s.emailSender.SendMentionNotification(member.User.Email, data); err != nil {
+				log.Printf("发送提及通知邮件失败: %v", err)
+			}
+		}
+	}()
+}
+
+// GetMyMentions 分页获取当前用户被提及的记录
+func (s *MentionService) GetMyMentions(userID uuid.UUID, cursor string, limit int) (*MentionFeedResponse, error) {
+	var beforeTime *time.Time
+	var beforeID *uuid.UUID
+	if cursor != "" {
+		var c mentionCursor
+		if err := pagination.DecodeCursor(cursor, &c); err != nil {
+			return nil, err
+		}
+		beforeTime, beforeID = &c.CreatedAt, &c.ID
+	}
+
+	limit = pagination.ClampLimit(limit)
+	mentions, err := s.mentionRepo.FindByUserID(userID, beforeTime, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &MentionFeedResponse{}
+	if len(mentions) > limit {
+		mentions = mentions[:limit]
+		last := mentions[len(mentions)-1]
+		next, err := pagination.EncodeCursor(mentionCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			return nil, err
+		}
+		feed.NextCursor = next
+		feed.HasMore = true
+	}
+	feed.Items = mentions
+
+	if feed.UnreadCount, err = s.mentionRepo.CountUnread(userID); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// MarkMentionsRead 将当前用户的提及全部标记为已读
+func (s *MentionService) MarkMentionsRead(userID uuid.UUID) error {
+	return s.mentionRepo.MarkAllRead(userID)
+}
+
+// displayName 成员在空间内的显示名，优先使用昵称
+func displayName(member *model.SpaceMember) string {
+	if member.Nickname != "" {
+		return member.Nickname
+	}
+	return member.User.Username
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/validator"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+type ReactionService struct {
+	reactionRepo *repository.ReactionRepository
+	eventRepo    *repository.EventRepository
+	spaceRepo    *repository.SpaceRepository
+}
+
+func NewReactionService(reactionRepo *repository.ReactionRepository, eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository) *ReactionService {
+	return &ReactionService{
+		reactionRepo: reactionRepo,
+		eventRepo:    eventRepo,
+		spaceRepo:    spaceRepo,
+	}
+}
+
+type AddReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// AddReaction 对事件添加表情回应，返回该事件最新的回应汇总
+func (s *ReactionService) AddReaction(eventID, userID uuid.UUID, req *AddReactionRequest) ([]model.ReactionSummary, error) {
+	if !validator.IsValidEmoji(req.Emoji) {
+		return nil, errors.New("无效的表情")
+	}
+	if err := s.checkReactable(eventID, userID); err != nil {
+		return nil, err
+	}
+
+	reaction := &model.EventReaction{
+		EventID: eventID,
+		UserID:  userID,
+		Emoji:   req.Emoji,
+	}
+	if err := s.reactionRepo.Add(reaction); err != nil {
+		return nil, err
+	}
+
+	return s.reactionRepo.Summaries(eventID, userID)
+}
+
+// RemoveReaction 取消自己的表情回应，返回该事件最新的回应汇总
+func (s *ReactionService) RemoveReaction(eventID, userID uuid.UUID, emoji string) ([]model.ReactionSummary, error) {
+	if err := s.checkReactable(eventID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.reactionRepo.Remove(eventID, userID, emoji); err != nil {
+		return nil, err
+	}
+
+	return s.reactionRepo.Summaries(eventID, userID)
+}
+
+// checkReactable 检查事件存在、用户为空间成员且空间未归档
+func (s *ReactionService) checkReactable(eventID, userID uuid.UUID) error {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return errors.New("事件不存在")
+	}
+
+	isMember, err := s.spaceRepo.IsUserInSpace(event.SpaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("无权访问该事件")
+	}
+	if !event.IsVisibleTo(userID, time.Now()) {
+		return errors.New("事件不存在")
+	}
+
+	archived, err := s.spaceRepo.IsArchived(event.SpaceID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return ErrSpaceArchived
+	}
+	return nil
+}
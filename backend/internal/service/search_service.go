@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/config"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/search"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+const (
+	maxSearchQueryLength = 100
+	snippetRadius        = 30
+)
+
+type SearchService struct {
+	eventRepo *repository.EventRepository
+	spaceRepo *repository.SpaceRepository
+}
+
+func NewSearchService(eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository) *SearchService {
+	return &SearchService{
+		eventRepo: eventRepo,
+		spaceRepo: spaceRepo,
+	}
+}
+
+type SearchResult struct {
+	model.Event
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func (r SearchResult) MarshalJSON() ([]byte, error) {
+	return model.MarshalEventWith(&r.Event, struct {
+		Rank    float64 `json:"rank"`
+		Snippet string  `json:"snippet"`
+	}{r.Rank, r.Snippet})
+}
+
+type searchCursor struct {
+	Offset int `json:"o"`
+}
+
+// SearchEvents 在空间内搜索事件的标题、内容、描述、地点和标签
+func (s *SearchService) SearchEvents(spaceID, userID uuid.UUID, query, cursor string, limit int) (*pagination.Page[SearchResult], error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("搜索关键词不能为空")
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, errors.New("搜索关键词过长")
+	}
+
+	var pos searchCursor
+	if cursor != "" {
+		if err := pagination.DecodeCursor(cursor, &pos); err != nil {
+			return nil, err
+		}
+	}
+	limit = pagination.ClampLimit(limit)
+
+	// 默认分词器无法切分中文，包含中日韩文字时使用 pg_trgm 模糊匹配
+	useTrigram := config.AppConfig.Search.CJKTrigram && search.ContainsCJK(query)
+
+	var hits []repository.SearchHit
+	if useTrigram {
+		hits, err = s.eventRepo.SearchTrigram(spaceID, userID, query, limit+1, pos.Offset)
+	} else {
+		hits, err = s.eventRepo.SearchFullText(spaceID, userID, config.AppConfig.Search.TSConfig, query, limit+1, pos.Offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page[SearchResult]{Items: []SearchResult{}}
+	if len(hits) > limit {
+		hits = hits[:limit]
+		next, err := pagination.EncodeCursor(searchCursor{Offset: pos.Offset + limit})
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+		page.HasMore = true
+	}
+
+	ids := make([]uuid.UUID, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	events, err := s.eventRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.AttachReactions(events, userID); err != nil {
+		return nil, err
+	}
+
+	hitByID := make(map[uuid.UUID]repository.SearchHit, len(hits))
+	for _, hit := range hits {
+		hitByID[hit.ID] = hit
+	}
+	for _, event := range events {
+		hit := hitByID[event.ID]
+		snippet := hit.Snippet
+		if useTrigram {
+			snippet = eventSnippet(&event, query)
+		}
+		page.Items = append(page.Items, SearchResult{
+			Event:   event,
+			Rank:    hit.Rank,
+			Snippet: snippet,
+		})
+	}
+
+	return page, nil
+}
+
+// eventSnippet 依次在标题、内容、描述、地点、标签中查找关键词并生成高亮片段
+func eventSnippet(event *model.Event, query string) string {
+	fields := []string{event.Title, event.Content, event.Description, event.Location, strings.Join(event.Tags, " ")}
+	for _, field := range fields {
+		if snippet := search.Snippet(field, query, snippetRadius); snippet != "" {
+			return snippet
+		}
+	}
+	return ""
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/utils"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+	"github.com/qq1477959747/linetime/backend/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShareLinkNotFound     = errors.New("分享链接不存在或已失效")
+	ErrSharePasswordRequired = errors.New("该分享需要访问密码")
+	ErrSharePasswordInvalid  = errors.New("访问密码错误")
+)
+
+type ShareService struct {
+	shareRepo *repository.ShareRepository
+	spaceRepo *repository.SpaceRepository
+	eventRepo *repository.EventRepository
+	storage   *storage.MinIOStorage
+}
+
+func NewShareService(shareRepo *repository.ShareRepository, spaceRepo *repository.SpaceRepository, eventRepo *repository.EventRepository, storage *storage.MinIOStorage) *ShareService {
+	return &ShareService{
+		shareRepo: shareRepo,
+		spaceRepo: spaceRepo,
+		eventRepo: eventRepo,
+		storage:   storage,
+	}
+}
+
+type CreateShareLinkRequest struct {
+	Scope     model.ShareScope `json:"scope" binding:"required"`
+	EventID   *uuid.UUID       `json:"event_id"`
+	StartDate *time.Time       `json:"start_date"`
+	EndDate   *time.Time       `json:"end_date"`
+	Password  string           `json:"password"`
+	ExpiresAt *time.Time       `json:"expires_at"`
+}
+
+type ShareLinkResponse struct {
+	*model.ShareLink
+	URL         string `json:"url"`
+	HasPassword bool   `json:"has_password"`
+}
+
+// SharedSpaceView 公开分享中的空间信息（不含邀请码与成员）
+type SharedSpaceView struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Type        model.SpaceType `json:"type"`
+}
+
+// SharedEventView 公开分享中的事件信息（不含邮箱等个人信息），日期和时间为事件时区的本地值
+type SharedEventView struct {
+	ID          uuid.UUID `json:"id"`
+	EventDate   string    `json:"event_date"`
+	EventTime   *string   `json:"event_time"`
+	EndDate     *string   `json:"end_date"`
+	EndTime     *string   `json:"end_time"`
+	TimeZone    string    `json:"time_zone"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	Tags        []string  `json:"tags"`
+	Images      []string  `json:"images"`
+	Author      string    `json:"author"`
+}
+
+type SharedContentResponse struct {
+	Scope      model.ShareScope  `json:"scope"`
+	Space      SharedSpaceView   `json:"space"`
+	Events     []SharedEventView `json:"events"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	HasMore    bool              `json:"has_more"`
+}
+
+// CreateShareLink 创建分享链接（空间成员均可创建）
+func (s *ShareService) CreateShareLink(spaceID, userID uuid.UUID, req *CreateShareLinkRequest) (*ShareLinkResponse, error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	link := &model.ShareLink{
+		SpaceID:   spaceID,
+		CreatedBy: userID,
+		Scope:     req.Scope,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	switch req.Scope {
+	case model.ShareScopeSpace:
+	case model.ShareScopeRange:
+		if req.StartDate == nil || req.EndDate == nil {
+			return nil, errors.New("请指定分享的开始和结束日期")
+		}
+		if req.EndDate.Before(*req.StartDate) {
+			return nil, errors.New("结束日期不能早于开始日期")
+		}
+		link.StartDate = req.StartDate
+		link.EndDate = req.EndDate
+	case model.ShareScopeEvent:
+		if req.EventID == nil {
+			return nil, errors.New("请指定要分享的事件")
+		}
+		event, err := s.eventRepo.FindByID(*req.EventID)
+		if err != nil || event.SpaceID != spaceID {
+			return nil, errors.New("事件不存在")
+		}
+		if !event.IsPublic(time.Now()) {
+			return nil, errors.New("只有对全体成员公开的事件可以分享")
+		}
+		link.EventID = req.EventID
+	default:
+		return nil, errors.New("无效的分享范围")
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("过期时间必须晚于当前时间")
+	}
+
+	if req.Password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = string(hashedPassword)
+	}
+
+	token, err := utils.GenerateToken(24)
+	if err != nil {
+		return nil, fmt.Errorf("生成分享令牌失败: %w", err)
+	}
+	link.Token = token
+
+	if err := s.shareRepo.Create(link); err != nil {
+		return nil, err
+	}
+
+	return toShareLinkResponse(link), nil
+}
+
+// GetShareLinks 获取空间的分享链接列表
+func (s *ShareService) GetShareLinks(spaceID, userID uuid.UUID) ([]*ShareLinkResponse, error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	links, err := s.shareRepo.FindBySpaceID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*ShareLinkResponse, 0, len(links))
+	for i := range links {
+		result = append(result, toShareLinkResponse(&links[i]))
+	}
+	return result, nil
+}
+
+// RevokeShareLink 撤销分享链接（创建者或空间 owner）
+func (s *ShareService) RevokeShareLink(spaceID, shareID, userID uuid.UUID) error {
+	link, err := s.shareRepo.FindByID(shareID)
+	if err != nil || link.SpaceID != spaceID {
+		return errors.New("分享链接不存在")
+	}
+
+	if link.CreatedBy != userID {
+		space, err := s.spaceRepo.FindByID(spaceID)
+		if err != nil {
+			return err
+		}
+		if space.OwnerID != userID {
+			return errors.New("只有创建者或空间创建者可以撤销分享")
+		}
+	}
+
+	return s.shareRepo.Revoke(shareID)
+}
+
+// GetSharedContent 通过分享令牌获取公开内容（无需登录）
+func (s *ShareService) GetSharedContent(ctx context.Context, token, password, cursor string, limit int) (*SharedContentResponse, error) {
+	link, err := s.shareRepo.FindByToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+	if !link.IsActive() {
+		return nil, ErrShareLinkNotFound
+	}
+
+	if link.HasPassword() {
+		if password == "" {
+			return nil, ErrSharePasswordRequired
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)); err != nil {
+			return nil, ErrSharePasswordInvalid
+		}
+	}
+
+	// 空间被删除后分享自动失效
+	space, err := s.spaceRepo.FindByID(link.SpaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+
+	page := &EventPage{}
+	if link.Scope == model.ShareScopeEvent {
+		event, err := s.eventRepo.FindByID(*link.EventID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrShareLinkNotFound
+			}
+			return nil, err
+		}
+		// 事件被改回草稿或收窄可见范围后分享同样失效
+		if !event.IsPublic(time.Now()) {
+			return nil, ErrShareLinkNotFound
+		}
+		page.Items = []model.Event{*event}
+	} else {
+		after, err := decodeEventCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		limit = pagination.ClampLimit(limit)
+
+		// 范围分享限定日期，整个空间分享不限日期
+		events, err := s.eventRepo.FindByDateRange(link.SpaceID, uuid.Nil, link.StartDate, link.EndDate, after, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		if page, err = buildEventPage(events, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &SharedContentResponse{
+		Scope: link.Scope,
+		Space: SharedSpaceView{
+			Name:        space.Name,
+			Description: space.Description,
+			Type:        space.Type,
+		},
+		Events:     make([]SharedEventView, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	for _, event := range page.Items {
+		result.Events = append(result.Events, SharedEventView{
+			ID:          event.ID,
+			EventDate:   event.EventDate.Format(localtime.DateLayout),
+			EventTime:   localtime.FormatClock(event.EventTime),
+			EndDate:     localtime.FormatDate(event.EndDate),
+			EndTime:     localtime.FormatClock(event.EndTime),
+			TimeZone:    event.TimeZone,
+			Title:       event.Title,
+			Content:     event.Content,
+			Description: event.Description,
+			Location:    event.Location,
+			Tags:        event.Tags,
+			Images:      s.presignImages(ctx, event.ImageURLs),
+			Author:      event.User.Username,
+		})
+	}
+
+	return result, nil
+}
+
+// presignImages 将图片地址转换为有时效的预签名地址
+func (s *ShareService) presignImages(ctx context.Context, urls []string) []string {
+	if s.storage == nil {
+		return urls
+	}
+
+	signed := make([]string, 0, len(urls))
+	for _, url := range urls {
+		signedURL, err := s.storage.GetFileURL(ctx, storage.GetObjectNameFromURL(url), storage.ShareURLExpiry)
+		if err != nil {
+			log.Printf("生成分享图片地址失败 %s: %v", url, err)
+			continue
+		}
+		signed = append(signed, signedURL)
+	}
+	return signed
+}
+
+func toShareLinkResponse(link *model.ShareLink) *ShareLinkResponse {
+	return &ShareLinkResponse{
+		ShareLink:   link,
+		URL:         fmt.Sprintf("https://linetime.app/share/%s", link.Token),
+		HasPassword: link.HasPassword(),
+	}
+}
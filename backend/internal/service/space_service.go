@@ -1,26 +1,37 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math/rand"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/config"
 	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/validator"
 	"github.com/qq1477959747/linetime/backend/internal/repository"
+	"github.com/qq1477959747/linetime/backend/internal/storage"
 	"gorm.io/gorm"
 )
 
 type SpaceService struct {
-	spaceRepo *repository.SpaceRepository
-	userRepo  *repository.UserRepository
+	spaceRepo       *repository.SpaceRepository
+	userRepo        *repository.UserRepository
+	storage         *storage.MinIOStorage
+	activityService *ActivityService
 }
 
-func NewSpaceService(spaceRepo *repository.SpaceRepository, userRepo *repository.UserRepository) *SpaceService {
+func NewSpaceService(spaceRepo *repository.SpaceRepository, userRepo *repository.UserRepository, storage *storage.MinIOStorage, activityService *ActivityService) *SpaceService {
 	return &SpaceService{
-		spaceRepo: spaceRepo,
-		userRepo:  userRepo,
+		spaceRepo:       spaceRepo,
+		userRepo:        userRepo,
+		storage:         storage,
+		activityService: activityService,
 	}
 }
 
@@ -39,6 +50,26 @@ type SpaceResponse struct {
 	MemberCount int `json:"member_count"`
 }
 
+// UpdateMemberProfileRequest 只修改请求中出现的字段，空字符串表示清除
+type UpdateMemberProfileRequest struct {
+	Nickname  *string `json:"nickname"`
+	Color     *string `json:"color"`
+	AvatarURL *string `json:"avatar_url"`
+}
+
+type UserSpaceResponse struct {
+	*model.Space
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type TrashedSpaceResponse struct {
+	*model.Space
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+var ErrSpaceArchived = errors.New("空间已归档，无法修改")
+
 // GenerateInviteCode 生成8位随机邀请码
 func GenerateInviteCode() string {
 	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -96,9 +127,29 @@ func (s *SpaceService) CreateSpace(req *CreateSpaceRequest, ownerID uuid.UUID) (
 	return space, nil
 }
 
-// GetUserSpaces 获取用户的所有空间
-func (s *SpaceService) GetUserSpaces(userID uuid.UUID) ([]model.Space, error) {
-	return s.spaceRepo.FindByUserID(userID)
+// GetUserSpaces 获取用户的所有空间，附带未读动态数
+func (s *SpaceService) GetUserSpaces(userID uuid.UUID) ([]UserSpaceResponse, error) {
+	spaces, err := s.spaceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var unread map[uuid.UUID]int64
+	if s.activityService != nil {
+		unread, err = s.activityService.UnreadCounts(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]UserSpaceResponse, 0, len(spaces))
+	for i := range spaces {
+		result = append(result, UserSpaceResponse{
+			Space:       &spaces[i],
+			UnreadCount: unread[spaces[i].ID],
+		})
+	}
+	return result, nil
 }
 
 // GetSpaceByID 获取空间详情
@@ -128,6 +179,10 @@ func (s *SpaceService) RefreshInviteCode(spaceID, userID uuid.UUID) (*model.Spac
 		return nil, errors.New("只有空间创建者可以刷新邀请码")
 	}
 
+	if space.IsArchived() {
+		return nil, ErrSpaceArchived
+	}
+
 	// 生成新的邀请码
 	space.InviteCode = GenerateInviteCode()
 	space.InviteLink = fmt.Sprintf("https://linetime.app/invite/%s", space.InviteCode)
@@ -136,6 +191,8 @@ func (s *SpaceService) RefreshInviteCode(spaceID, userID uuid.UUID) (*model.Spac
 		return nil, err
 	}
 
+	s.recordActivity(space.ID, userID, model.ActivityInviteRefreshed, nil, "")
+
 	return space, nil
 }
 
@@ -155,6 +212,11 @@ func (s *SpaceService) JoinSpace(inviteCode string, userID uuid.UUID) (*model.Sp
 		return nil, errors.New("不能加入自己创建的空间")
 	}
 
+	// 已归档的空间不再接受新成员
+	if space.IsArchived() {
+		return nil, ErrSpaceArchived
+	}
+
 	// 检查用户是否已在该空间
 	isMember, err := s.spaceRepo.IsUserInSpace(space.ID, userID)
 	if err != nil {
@@ -175,6 +237,8 @@ func (s *SpaceService) JoinSpace(inviteCode string, userID uuid.UUID) (*model.Sp
 		return nil, err
 	}
 
+	s.recordActivity(space.ID, userID, model.ActivityMemberJoined, &userID, "")
+
 	return space, nil
 }
 
@@ -191,6 +255,10 @@ func (s *SpaceService) RemoveMember(spaceID, userID, targetUserID uuid.UUID) err
 		return errors.New("只有空间创建者可以移除成员")
 	}
 
+	if space.IsArchived() {
+		return ErrSpaceArchived
+	}
+
 	// 不能移除自己
 	if userID == targetUserID {
 		return errors.New("不能移除自己")
@@ -201,6 +269,8 @@ func (s *SpaceService) RemoveMember(spaceID, userID, targetUserID uuid.UUID) err
 		return err
 	}
 
+	s.recordActivity(spaceID, userID, model.ActivityMemberRemoved, &targetUserID, "")
+
 	// 如果被移除的用户将此空间设为默认空间，则清除其默认空间设置
 	if s.userRepo != nil {
 		targetUser, err := s.userRepo.FindByID(targetUserID)
@@ -226,7 +296,56 @@ func (s *SpaceService) GetSpaceMembers(spaceID, userID uuid.UUID) ([]model.Space
 	return s.spaceRepo.GetMembers(spaceID)
 }
 
-// DeleteSpace 删除空间（只有 owner 可以删除）
+// UpdateMemberProfile 成员修改自己在空间内的昵称、颜色和头像
+func (s *SpaceService) UpdateMemberProfile(spaceID, userID uuid.UUID, req *UpdateMemberProfileRequest) (*model.SpaceMember, error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	archived, err := s.spaceRepo.IsArchived(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if archived {
+		return nil, ErrSpaceArchived
+	}
+
+	updates := make(map[string]interface{})
+	if req.Nickname != nil {
+		nickname := strings.TrimSpace(*req.Nickname)
+		if utf8.RuneCountInString(nickname) > 50 {
+			return nil, errors.New("昵称不能超过50个字符")
+		}
+		updates["nickname"] = nickname
+	}
+	if req.Color != nil {
+		if *req.Color != "" && !validator.IsValidHexColor(*req.Color) {
+			return nil, errors.New("颜色格式不正确，应为 #RRGGBB")
+		}
+		updates["color"] = *req.Color
+	}
+	if req.AvatarURL != nil {
+		if *req.AvatarURL != "" && !strings.HasPrefix(*req.AvatarURL, "http://") && !strings.HasPrefix(*req.AvatarURL, "https://") {
+			return nil, errors.New("头像地址格式不正确")
+		}
+		updates["avatar_url"] = *req.AvatarURL
+	}
+
+	if len(updates) > 0 {
+		if err := s.spaceRepo.UpdateMemberProfile(spaceID, userID, updates); err != nil {
+			return nil, err
+		}
+		InvalidateSpaceStats(spaceID)
+	}
+
+	return s.spaceRepo.FindMember(spaceID, userID)
+}
+
+// DeleteSpace 删除空间（只有 owner 可以删除），空间会进入回收站并在保留期后彻底清理
 func (s *SpaceService) DeleteSpace(spaceID, userID uuid.UUID) error {
 	// 获取空间
 	space, err := s.spaceRepo.FindByID(spaceID)
@@ -242,11 +361,138 @@ func (s *SpaceService) DeleteSpace(spaceID, userID uuid.UUID) error {
 		return errors.New("只有空间创建者可以删除空间")
 	}
 
-	// 清除所有将此空间设为默认空间的用户的默认空间设置
-	if s.userRepo != nil {
-		_ = s.userRepo.ClearDefaultSpaceForSpace(spaceID)
+	// 移入回收站（事件一并软删除，成员关系保留），以此为默认空间的用户设置会被清除，恢复时还原
+	return s.spaceRepo.MoveToTrash(spaceID)
+}
+
+// ArchiveSpace 归档空间（只有 owner 可以归档），归档后空间只读
+func (s *SpaceService) ArchiveSpace(spaceID, userID uuid.UUID) (*model.Space, error) {
+	return s.setArchived(spaceID, userID, true)
+}
+
+// UnarchiveSpace 取消归档
+func (s *SpaceService) UnarchiveSpace(spaceID, userID uuid.UUID) (*model.Space, error) {
+	return s.setArchived(spaceID, userID, false)
+}
+
+func (s *SpaceService) setArchived(spaceID, userID uuid.UUID, archived bool) (*model.Space, error) {
+	space, err := s.spaceRepo.FindByID(spaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("空间不存在")
+		}
+		return nil, err
+	}
+
+	if space.OwnerID != userID {
+		return nil, errors.New("只有空间创建者可以归档空间")
+	}
+
+	var archivedAt *time.Time
+	if archived {
+		if space.IsArchived() {
+			return space, nil
+		}
+		now := time.Now()
+		archivedAt = &now
+	}
+
+	if err := s.spaceRepo.SetArchivedAt(spaceID, archivedAt); err != nil {
+		return nil, err
+	}
+	space.ArchivedAt = archivedAt
+
+	return space, nil
+}
+
+// GetTrashedSpaces 获取用户回收站中的空间
+func (s *SpaceService) GetTrashedSpaces(userID uuid.UUID) ([]TrashedSpaceResponse, error) {
+	spaces, err := s.spaceRepo.FindTrashedByOwner(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	retention := spaceTrashRetention()
+	result := make([]TrashedSpaceResponse, 0, len(spaces))
+	for i := range spaces {
+		deletedAt := spaces[i].DeletedAt.Time
+		result = append(result, TrashedSpaceResponse{
+			Space:     &spaces[i],
+			DeletedAt: deletedAt,
+			PurgeAt:   deletedAt.Add(retention),
+		})
+	}
+
+	return result, nil
+}
+
+// RestoreSpace 从回收站恢复空间（只有 owner 可以恢复）
+func (s *SpaceService) RestoreSpace(spaceID, userID uuid.UUID) (*model.Space, error) {
+	space, err := s.spaceRepo.FindTrashedByID(spaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("回收站中不存在该空间")
+		}
+		return nil, err
+	}
+
+	if space.OwnerID != userID {
+		return nil, errors.New("只有空间创建者可以恢复空间")
 	}
 
-	// 删除空间（会级联删除成员关系和事件）
-	return s.spaceRepo.DeleteWithRelations(spaceID)
+	if err := s.spaceRepo.Restore(space); err != nil {
+		return nil, err
+	}
+	InvalidateSpaceStats(spaceID)
+
+	return s.spaceRepo.FindByID(spaceID)
+}
+
+// PurgeExpiredSpaces 彻底清理超过保留期的空间，包括事件、图片记录和存储中的文件
+func (s *SpaceService) PurgeExpiredSpaces(ctx context.Context) error {
+	spaces, err := s.spaceRepo.FindTrashedBefore(time.Now().Add(-spaceTrashRetention()))
+	if err != nil {
+		return err
+	}
+
+	for _, space := range spaces {
+		if err := s.purgeSpace(ctx, space.ID); err != nil {
+			// 单个空间失败不影响其他空间，下次任务会重试
+			log.Printf("清理空间 %s 失败: %v", space.ID, err)
+			continue
+		}
+		log.Printf("空间 %s 已彻底清理", space.ID)
+	}
+
+	return nil
+}
+
+func (s *SpaceService) purgeSpace(ctx context.Context, spaceID uuid.UUID) error {
+	// 先删除存储中的文件，失败时保留数据库记录以便重试
+	if s.storage != nil {
+		urls, err := s.spaceRepo.FindImageURLs(spaceID)
+		if err != nil {
+			return err
+		}
+		for _, url := range urls {
+			if url == "" {
+				continue
+			}
+			if err := s.storage.DeleteFile(ctx, storage.GetObjectNameFromURL(url)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.spaceRepo.PurgeWithRelations(spaceID)
+}
+
+func (s *SpaceService) recordActivity(spaceID, actorID uuid.UUID, activityType model.ActivityType, targetID *uuid.UUID, summary string) {
+	if s.activityService != nil {
+		s.activityService.Record(spaceID, actorID, activityType, targetID, summary)
+	}
+}
+
+func spaceTrashRetention() time.Duration {
+	return time.Duration(config.AppConfig.Trash.SpaceRetentionDays) * 24 * time.Hour
 }
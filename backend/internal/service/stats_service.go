@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+	"github.com/qq1477959747/linetime/backend/internal/storage"
+)
+
+const (
+	spaceStatsKeyPrefix = "space_stats:"
+	spaceStatsTTL       = 1 * time.Hour
+	statsTopLimit       = 10
+)
+
+type StatsService struct {
+	statsRepo *repository.StatsRepository
+	spaceRepo *repository.SpaceRepository
+}
+
+func NewStatsService(statsRepo *repository.StatsRepository, spaceRepo *repository.SpaceRepository) *StatsService {
+	return &StatsService{
+		statsRepo: statsRepo,
+		spaceRepo: spaceRepo,
+	}
+}
+
+type SpaceStats struct {
+	repository.EventTotals
+	LongestStreak repository.Streak        `json:"longest_streak"`
+	ByMonth       []repository.PeriodCount `json:"by_month"`
+	ByYear        []repository.PeriodCount `json:"by_year"`
+	ByMember      []repository.MemberCount `json:"by_member"`
+	TopTags       []repository.ValueCount  `json:"top_tags"`
+	TopLocations  []repository.ValueCount  `json:"top_locations"`
+	GeneratedAt   time.Time                `json:"generated_at"`
+}
+
+// GetSpaceStats 获取空间统计数据，优先读取 Redis 缓存
+func (s *StatsService) GetSpaceStats(ctx context.Context, spaceID, userID uuid.UUID) (*SpaceStats, error) {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("无权访问该空间")
+	}
+
+	key := spaceStatsKeyPrefix + spaceID.String()
+	if cached, err := storage.Get(ctx, key); err == nil {
+		var stats SpaceStats
+		if err := json.Unmarshal([]byte(cached), &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	stats, err := s.computeStats(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(stats); err == nil {
+		if err := storage.Set(ctx, key, string(data), spaceStatsTTL); err != nil {
+			log.Printf("缓存空间统计失败: %v", err)
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) computeStats(spaceID uuid.UUID) (*SpaceStats, error) {
+	totals, err := s.statsRepo.Totals(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	streak, err := s.statsRepo.LongestStreak(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	byMonth, err := s.statsRepo.CountByPeriod(spaceID, "YYYY-MM")
+	if err != nil {
+		return nil, err
+	}
+	byYear, err := s.statsRepo.CountByPeriod(spaceID, "YYYY")
+	if err != nil {
+		return nil, err
+	}
+	byMember, err := s.statsRepo.CountByMember(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	topTags, err := s.statsRepo.TopTags(spaceID, statsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	topLocations, err := s.statsRepo.TopLocations(spaceID, statsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpaceStats{
+		EventTotals:   *totals,
+		LongestStreak: *streak,
+		ByMonth:       byMonth,
+		ByYear:        byYear,
+		ByMember:      byMember,
+		TopTags:       topTags,
+		TopLocations:  topLocations,
+		GeneratedAt:   time.Now(),
+	}, nil
+}
+
+// InvalidateSpaceStats 清除空间统计缓存，事件写入后调用
+func InvalidateSpaceStats(spaceID uuid.UUID) {
+	if storage.RedisClient == nil {
+		return
+	}
+	if err := storage.Delete(context.Background(), spaceStatsKeyPrefix+spaceID.String()); err != nil {
+		log.Printf("清除空间统计缓存失败: %v", err)
+	}
+}
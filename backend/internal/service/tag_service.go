@@ -0,0 +1,349 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/validator"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+const maxTagLength = 50
+
+type TagService struct {
+	tagRepo   *repository.TagRepository
+	spaceRepo *repository.SpaceRepository
+}
+
+func NewTagService(tagRepo *repository.TagRepository, spaceRepo *repository.SpaceRepository) *TagService {
+	return &TagService{
+		tagRepo:   tagRepo,
+		spaceRepo: spaceRepo,
+	}
+}
+
+type CreateTagRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Color   string   `json:"color"`
+	Aliases []string `json:"aliases"`
+}
+
+type UpdateTagRequest struct {
+	Name    *string  `json:"name"`
+	Color   *string  `json:"color"`
+	Aliases []string `json:"aliases"`
+}
+
+type MergeTagsRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required"`
+	TargetID  uuid.UUID   `json:"target_id" binding:"required"`
+}
+
+// ListTags 获取空间标签及当前用户可见事件中的使用次数。
+// 可见事件中出现但尚未登记的标签（如定时发布后才公开的事件）以未登记（ID 为空）的形式返回，查询本身不写入
+func (s *TagService) ListTags(spaceID, userID uuid.UUID) ([]model.SpaceTag, error) {
+	if err := s.checkMember(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.tagRepo.UsageCounts(spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.FindBySpaceID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		usage[strings.ToLower(normalizeTag(count.Value))] += count.Count
+	}
+	for i := range tags {
+		key := strings.ToLower(tags[i].Name)
+		tags[i].UsageCount = usage[key]
+		delete(usage, key)
+	}
+	for _, count := range counts {
+		name := normalizeTag(count.Value)
+		if n, ok := usage[strings.ToLower(name)]; ok && name != "" {
+			tags = append(tags, model.SpaceTag{SpaceID: spaceID, Name: name, Aliases: []string{}, UsageCount: n})
+			delete(usage, strings.ToLower(name))
+		}
+	}
+	return tags, nil
+}
+
+// CreateTag 登记新标签
+func (s *TagService) CreateTag(spaceID, userID uuid.UUID, req *CreateTagRequest) (*model.SpaceTag, error) {
+	if err := s.checkWritable(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.FindBySpaceID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &model.SpaceTag{SpaceID: spaceID}
+	if err := applyTagFields(tag, tags, req.Name, req.Color, req.Aliases); err != nil {
+		return nil, err
+	}
+
+	// 别名对应的历史写法一并归并到新标签
+	if err := s.tagRepo.Save(tag, tag.Aliases); err != nil {
+		return nil, err
+	}
+
+	InvalidateSpaceStats(spaceID)
+	return tag, nil
+}
+
+// UpdateTag 修改标签名称、颜色或别名，改名会同步更新所有事件，旧名称保留为别名
+func (s *TagService) UpdateTag(spaceID, tagID, userID uuid.UUID, req *UpdateTagRequest) (*model.SpaceTag, error) {
+	if err := s.checkWritable(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.findTag(spaceID, tagID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.FindBySpaceID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := tag.Name
+	name, color, aliases := tag.Name, tag.Color, tag.Aliases
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.Color != nil {
+		color = *req.Color
+	}
+	if req.Aliases != nil {
+		aliases = req.Aliases
+	}
+
+	renamed := !strings.EqualFold(normalizeTag(name), oldName)
+	if renamed {
+		aliases = append(aliases, oldName)
+	}
+	if err := applyTagFields(tag, tags, name, color, aliases); err != nil {
+		return nil, err
+	}
+
+	from := append([]string{oldName}, tag.Aliases...)
+	if err := s.tagRepo.Save(tag, from); err != nil {
+		return nil, err
+	}
+
+	InvalidateSpaceStats(spaceID)
+	return tag, nil
+}
+
+// MergeTags 将多个标签合并到目标标签，来源标签名及其别名成为目标的别名
+func (s *TagService) MergeTags(spaceID, userID uuid.UUID, req *MergeTagsRequest) (*model.SpaceTag, error) {
+	if err := s.checkWritable(spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	target, err := s.findTag(spaceID, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := append([]string{}, target.Aliases...)
+	from := []string{}
+	sources := make([]model.SpaceTag, 0, len(req.SourceIDs))
+	for _, sourceID := range req.SourceIDs {
+		if sourceID == target.ID {
+			continue
+		}
+		source, err := s.findTag(spaceID, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, *source)
+		aliases = append(aliases, source.Name)
+		aliases = append(aliases, source.Aliases...)
+		from = append(from, source.Name)
+		from = append(from, source.Aliases...)
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("请选择要合并的标签")
+	}
+	target.Aliases = dedupeTags(aliases, target.Name)
+
+	if err := s.tagRepo.Merge(target, sources, from); err != nil {
+		return nil, err
+	}
+
+	InvalidateSpaceStats(spaceID)
+	return target, nil
+}
+
+// DeleteTag 删除标签，并从所有事件中移除该标签及其别名
+func (s *TagService) DeleteTag(spaceID, tagID, userID uuid.UUID) error {
+	if err := s.checkWritable(spaceID, userID); err != nil {
+		return err
+	}
+
+	tag, err := s.findTag(spaceID, tagID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tagRepo.Delete(tag, append([]string{tag.Name}, tag.Aliases...)); err != nil {
+		return err
+	}
+
+	InvalidateSpaceStats(spaceID)
+	return nil
+}
+
+// NormalizeTags 规范化事件标签：去除多余空白、按别名归并到标准名称、忽略大小写去重。
+// 新标签不在这里登记，事件保存且对全体成员公开后再由 RegisterTags 登记，避免私密事件的标签泄露
+func (s *TagService) NormalizeTags(spaceID uuid.UUID, input []string) ([]string, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	tags, err := s.tagRepo.FindBySpaceID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	canonical := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		canonical[strings.ToLower(tag.Name)] = tag.Name
+		for _, alias := range tag.Aliases {
+			canonical[strings.ToLower(alias)] = tag.Name
+		}
+	}
+
+	result := make([]string, 0, len(input))
+	seen := make(map[string]bool, len(input))
+	for _, raw := range input {
+		name := normalizeTag(raw)
+		if name == "" {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxTagLength {
+			return nil, errors.New("标签长度不能超过50个字符")
+		}
+		if known, ok := canonical[strings.ToLower(name)]; ok {
+			name = known
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+// RegisterTags 登记事件中尚未登记的标签，tags 应已经过 NormalizeTags 规范化
+func (s *TagService) RegisterTags(spaceID uuid.UUID, tags []string) error {
+	return s.tagRepo.EnsureTags(spaceID, tags)
+}
+
+func (s *TagService) findTag(spaceID, tagID uuid.UUID) (*model.SpaceTag, error) {
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil || tag.SpaceID != spaceID {
+		return nil, errors.New("标签不存在")
+	}
+	return tag, nil
+}
+
+func (s *TagService) checkMember(spaceID, userID uuid.UUID) error {
+	isMember, err := s.spaceRepo.IsUserInSpace(spaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("无权访问该空间")
+	}
+	return nil
+}
+
+func (s *TagService) checkWritable(spaceID, userID uuid.UUID) error {
+	if err := s.checkMember(spaceID, userID); err != nil {
+		return err
+	}
+	archived, err := s.spaceRepo.IsArchived(spaceID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return ErrSpaceArchived
+	}
+	return nil
+}
+
+// applyTagFields 校验并写入标签名、颜色和别名，名称和别名不能与空间内其他标签重复
+func applyTagFields(tag *model.SpaceTag, existing []model.SpaceTag, name, color string, aliases []string) error {
+	name = normalizeTag(name)
+	if name == "" {
+		return errors.New("标签名称不能为空")
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return errors.New("标签长度不能超过50个字符")
+	}
+	if color != "" && !validator.IsValidHexColor(color) {
+		return errors.New("颜色格式应为 #RRGGBB")
+	}
+	aliases = dedupeTags(aliases, name)
+
+	taken := make(map[string]bool)
+	for _, other := range existing {
+		if other.ID == tag.ID {
+			continue
+		}
+		taken[strings.ToLower(other.Name)] = true
+		for _, alias := range other.Aliases {
+			taken[strings.ToLower(alias)] = true
+		}
+	}
+	if taken[strings.ToLower(name)] {
+		return errors.New("标签已存在，请使用合并功能")
+	}
+	for _, alias := range aliases {
+		if utf8.RuneCountInString(alias) > maxTagLength {
+			return errors.New("标签长度不能超过50个字符")
+		}
+		if taken[strings.ToLower(alias)] {
+			return errors.New("别名 " + alias + " 已被其他标签使用")
+		}
+	}
+
+	tag.Name = name
+	tag.Color = color
+	tag.Aliases = aliases
+	return nil
+}
+
+// dedupeTags 规范化并忽略大小写去重，同时排除与 exclude 相同的项
+func dedupeTags(tags []string, exclude string) []string {
+	result := make([]string, 0, len(tags))
+	seen := map[string]bool{strings.ToLower(exclude): true}
+	for _, raw := range tags {
+		name := normalizeTag(raw)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// normalizeTag 去除首尾空白并把连续空白压缩为一个空格
+func normalizeTag(tag string) string {
+	return strings.Join(strings.Fields(tag), " ")
+}
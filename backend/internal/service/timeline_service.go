@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/qq1477959747/linetime/backend/config"
+	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/pagination"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/search"
+	"github.com/qq1477959747/linetime/backend/internal/repository"
+)
+
+type TimelineService struct {
+	eventRepo *repository.EventRepository
+	spaceRepo *repository.SpaceRepository
+	userRepo  *repository.UserRepository
+}
+
+func NewTimelineService(eventRepo *repository.EventRepository, spaceRepo *repository.SpaceRepository, userRepo *repository.UserRepository) *TimelineService {
+	return &TimelineService{
+		eventRepo: eventRepo,
+		spaceRepo: spaceRepo,
+		userRepo:  userRepo,
+	}
+}
+
+type TimelineRequest struct {
+	SpaceIDs  []uuid.UUID
+	StartDate *time.Time
+	EndDate   *time.Time
+	Tags      []string
+	AuthorID  *uuid.UUID
+	Query     string
+	Cursor    string
+	Limit     int
+}
+
+// GetTimeline 合并用户所在全部空间的事件，按时间倒序分页
+func (s *TimelineService) GetTimeline(req *TimelineRequest, userID uuid.UUID) (*EventPage, error) {
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, errors.New("结束日期不能早于开始日期")
+	}
+
+	spaces, err := s.spaceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 指定空间时只保留用户所在的空间，避免越权读取
+	joined := make(map[uuid.UUID]bool, len(spaces))
+	for _, space := range spaces {
+		joined[space.ID] = true
+	}
+	spaceIDs := make([]uuid.UUID, 0, len(spaces))
+	if len(req.SpaceIDs) > 0 {
+		for _, id := range req.SpaceIDs {
+			if !joined[id] {
+				return nil, errors.New("无权访问该空间")
+			}
+			spaceIDs = append(spaceIDs, id)
+		}
+	} else {
+		for _, space := range spaces {
+			spaceIDs = append(spaceIDs, space.ID)
+		}
+	}
+
+	query := strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, errors.New("搜索关键词过长")
+	}
+
+	after, err := decodeEventCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := pagination.ClampLimit(req.Limit)
+
+	filter := &repository.TimelineFilter{
+		SpaceIDs:  spaceIDs,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Tags:      req.Tags,
+		AuthorID:  req.AuthorID,
+		Query:     query,
+		TSConfig:  config.AppConfig.Search.TSConfig,
+		Trigram:   config.AppConfig.Search.CJKTrigram && search.ContainsCJK(query),
+		ViewerID:  userID,
+	}
+
+	events, err := s.eventRepo.FindTimeline(filter, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	return buildEventPage(events, limit)
+}
+
+// 单次“那年今日”最多返回的事件数
+const maxOnThisDayEvents = 100
+
+type OnThisDayYear struct {
+	Year     int           `json:"year"`
+	YearsAgo int           `json:"years_ago"`
+	Events   []model.Event `json:"events"`
+}
+
+type OnThisDayResponse struct {
+	Date  string          `json:"date"`
+	Years []OnThisDayYear `json:"years"`
+}
+
+// GetOnThisDay 获取用户所在全部空间中往年同一天发生的事件，按年份倒序分组。
+// date 为空时取用户所在时区的当天
+func (s *TimelineService) GetOnThisDay(userID uuid.UUID, date *time.Time) (*OnThisDayResponse, error) {
+	var day time.Time
+	if date != nil {
+		day = *date
+	} else {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		day = localtime.Today(user.TimeZone, time.Now())
+	}
+
+	spaces, err := s.spaceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	spaceIDs := make([]uuid.UUID, 0, len(spaces))
+	for _, space := range spaces {
+		spaceIDs = append(spaceIDs, space.ID)
+	}
+
+	// 平年的 2 月 28 日同时展示闰年 2 月 29 日的事件
+	days := []int{day.Day()}
+	if day.Month() == time.February && day.Day() == 28 && !isLeapYear(day.Year()) {
+		days = append(days, 29)
+	}
+	startOfYear := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	events, err := s.eventRepo.FindOnThisDay(spaceIDs, userID, int(day.Month()), days, startOfYear, maxOnThisDayEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OnThisDayResponse{
+		Date:  day.Format("2006-01-02"),
+		Years: []OnThisDayYear{},
+	}
+	for _, event := range events {
+		year := event.EventDate.Year()
+		if n := len(result.Years); n == 0 || result.Years[n-1].Year != year {
+			result.Years = append(result.Years, OnThisDayYear{Year: year, YearsAgo: day.Year() - year})
+		}
+		last := &result.Years[len(result.Years)-1]
+		last.Events = append(last.Events, event)
+	}
+	return result, nil
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/qq1477959747/linetime/backend/internal/model"
+	"github.com/qq1477959747/linetime/backend/internal/pkg/localtime"
 	"github.com/qq1477959747/linetime/backend/internal/repository"
 )
 
@@ -55,3 +56,16 @@ func (s *UserService) GetUserByID(userID uuid.UUID) (*model.User, error) {
 func (s *UserService) ClearDefaultSpaceForSpace(spaceID uuid.UUID) error {
 	return s.userRepo.ClearDefaultSpaceForSpace(spaceID)
 }
+
+// SetOnThisDayDigest turns the daily "on this day" email on or off
+func (s *UserService) SetOnThisDayDigest(userID uuid.UUID, enabled bool) error {
+	return s.userRepo.UpdateOnThisDayDigest(userID, enabled)
+}
+
+// SetTimeZone sets the IANA time zone used as the default for new events
+func (s *UserService) SetTimeZone(userID uuid.UUID, timeZone string) error {
+	if _, err := localtime.LoadZone(timeZone); err != nil {
+		return err
+	}
+	return s.userRepo.UpdateTimeZone(userID, timeZone)
+}
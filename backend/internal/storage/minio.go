@@ -4,13 +4,23 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"path/filepath"
+	"net/url"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/qq1477959747/linetime/backend/config"
 )
 
+const (
+	// ShareURLExpiry 分享页面中图片预签名地址的有效期
+	ShareURLExpiry = time.Hour
+	// MaxURLExpiry S3 签名 V4 允许的最长有效期，用于邮件等需要长期可访问的地址
+	MaxURLExpiry = 7 * 24 * time.Hour
+)
+
 type MinIOStorage struct {
 	client *minio.Client
 	bucket string
@@ -75,18 +85,30 @@ func (s *MinIOStorage) DeleteFile(ctx context.Context, objectName string) error
 	return nil
 }
 
-// GetFileURL 获取文件访问 URL（预签名）
-func (s *MinIOStorage) GetFileURL(ctx context.Context, objectName string) (string, error) {
-	// 生成 1小时有效期的预签名 URL
-	url, err := s.client.PresignedGetObject(ctx, s.bucket, objectName, 3600, nil)
+// GetFileURL 获取有效期为 expiry 的预签名文件访问 URL
+func (s *MinIOStorage) GetFileURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	url, err := s.client.PresignedGetObject(ctx, s.bucket, objectName, expiry, nil)
 	if err != nil {
 		return "", fmt.Errorf("生成预签名 URL 失败: %w", err)
 	}
 	return url.String(), nil
 }
 
-// GetObjectNameFromURL 从 URL 中提取对象名称
-func GetObjectNameFromURL(url string) string {
-	// 简单实现：从 URL 中提取最后一部分作为对象名
-	return filepath.Base(url)
+// GetObjectNameFromURL 从 URL 中提取对象名称（去掉 endpoint 与 bucket 前缀）
+func GetObjectNameFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return path.Base(rawURL)
+	}
+
+	objectName := strings.TrimPrefix(u.Path, "/")
+	return strings.TrimPrefix(objectName, config.AppConfig.MinIO.Bucket+"/")
+}
+
+// ThumbnailURLFor 根据原图地址推算上传时生成的缩略图地址，无法推算时返回原图地址
+func ThumbnailURLFor(imageURL string) string {
+	if strings.Contains(imageURL, "/images/original/") {
+		return strings.Replace(imageURL, "/images/original/", "/images/thumbnails/", 1)
+	}
+	return imageURL
 }
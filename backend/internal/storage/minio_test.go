@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// 指定 Region 后生成预签名地址不需要访问服务端
+func newTestStorage(t *testing.T) *MinIOStorage {
+	client, err := minio.New("localhost:9000", &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("minio.New: %v", err)
+	}
+	return &MinIOStorage{client: client, bucket: "linetime"}
+}
+
+func TestGetFileURL(t *testing.T) {
+	s := newTestStorage(t)
+
+	cases := []struct {
+		name   string
+		expiry time.Duration
+		want   string
+	}{
+		{"share expiry", ShareURLExpiry, "3600"},
+		{"max expiry", MaxURLExpiry, "604800"},
+	}
+	for _, c := range cases {
+		signed, err := s.GetFileURL(context.Background(), "images/original/photo.jpg", c.expiry)
+		if err != nil {
+			t.Fatalf("%s: GetFileURL error = %v", c.name, err)
+		}
+		u, err := url.Parse(signed)
+		if err != nil {
+			t.Fatalf("%s: invalid URL %q: %v", c.name, signed, err)
+		}
+		if u.Path != "/linetime/images/original/photo.jpg" {
+			t.Errorf("%s: path = %q", c.name, u.Path)
+		}
+		if got := u.Query().Get("X-Amz-Expires"); got != c.want {
+			t.Errorf("%s: X-Amz-Expires = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestGetFileURLRejectsSubSecondExpiry(t *testing.T) {
+	s := newTestStorage(t)
+	if _, err := s.GetFileURL(context.Background(), "images/original/photo.jpg", 3600); err == nil {
+		t.Error("expected error for a 3600ns expiry")
+	}
+}
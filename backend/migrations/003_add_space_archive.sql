@@ -0,0 +1,6 @@
+-- Migration: Add archived state to spaces
+-- Archived spaces are read-only; deleted spaces stay in the trash (deleted_at) until purged
+
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_spaces_archived_at ON spaces(archived_at);
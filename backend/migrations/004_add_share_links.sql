@@ -0,0 +1,19 @@
+-- Migration: Public read-only share links for a space, a date range or a single event
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID PRIMARY KEY,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    event_id UUID,
+    start_date DATE,
+    end_date DATE,
+    password_hash VARCHAR(255),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_space_id ON share_links(space_id);
@@ -0,0 +1,15 @@
+-- Migration: Space activity feed and per-member last seen markers
+
+CREATE TABLE IF NOT EXISTS space_activities (
+    id UUID PRIMARY KEY,
+    space_id UUID NOT NULL,
+    actor_id UUID NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    target_id UUID,
+    summary VARCHAR(200),
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_space_activity ON space_activities(space_id, created_at);
+
+ALTER TABLE space_members ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
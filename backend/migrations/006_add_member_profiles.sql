@@ -0,0 +1,5 @@
+-- Migration: Per-space member profile (nickname, color, avatar override)
+
+ALTER TABLE space_members ADD COLUMN IF NOT EXISTS nickname VARCHAR(50);
+ALTER TABLE space_members ADD COLUMN IF NOT EXISTS color VARCHAR(20);
+ALTER TABLE space_members ADD COLUMN IF NOT EXISTS avatar_url TEXT;
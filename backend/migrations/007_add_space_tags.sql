@@ -0,0 +1,16 @@
+-- Migration: Per-space tag registry with colors and aliases, GIN index for tag filtering
+
+CREATE TABLE IF NOT EXISTS space_tags (
+    id UUID PRIMARY KEY,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(20),
+    aliases JSONB,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_space_tags_space_id ON space_tags(space_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_space_tags_space_name ON space_tags (space_id, lower(name));
+
+CREATE INDEX IF NOT EXISTS idx_events_tags ON events USING GIN (tags jsonb_path_ops);
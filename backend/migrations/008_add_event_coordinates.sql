@@ -0,0 +1,7 @@
+-- Migration: Optional coordinates and place name on events for the map view
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS place_name VARCHAR(200);
+
+CREATE INDEX IF NOT EXISTS idx_event_geo ON events(latitude, longitude);
@@ -0,0 +1,21 @@
+-- Migration: Make event_images the source of truth for event photos
+-- Copies the legacy events.image_urls JSONB list into event_images, then clears it
+
+ALTER TABLE event_images ADD COLUMN IF NOT EXISTS width INTEGER;
+ALTER TABLE event_images ADD COLUMN IF NOT EXISTS height INTEGER;
+
+INSERT INTO event_images (id, event_id, image_url, thumbnail_url, sort_order, uploaded_at)
+SELECT
+    gen_random_uuid(),
+    events.id,
+    img.url,
+    replace(img.url, '/images/original/', '/images/thumbnails/'),
+    img.ord - 1,
+    NOW()
+FROM events
+CROSS JOIN LATERAL jsonb_array_elements_text(events.image_urls) WITH ORDINALITY AS img(url, ord)
+WHERE jsonb_typeof(events.image_urls) = 'array'
+  AND img.url <> ''
+  AND NOT EXISTS (SELECT 1 FROM event_images WHERE event_images.event_id = events.id);
+
+UPDATE events SET image_urls = NULL WHERE image_urls IS NOT NULL;
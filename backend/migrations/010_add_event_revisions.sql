@@ -0,0 +1,15 @@
+-- Migration: Revision history for event edits
+-- snapshot holds the event content before the edit, changes holds the per-field diff
+
+CREATE TABLE IF NOT EXISTS event_revisions (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    editor_id UUID NOT NULL,
+    changes JSONB,
+    snapshot JSONB,
+    created_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_revision ON event_revisions(event_id, revision);
+CREATE INDEX IF NOT EXISTS idx_event_revisions_created_at ON event_revisions(created_at);
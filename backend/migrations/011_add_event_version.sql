@@ -0,0 +1,3 @@
+-- Migration: Optimistic concurrency version for events
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
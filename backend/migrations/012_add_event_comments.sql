@@ -0,0 +1,19 @@
+-- Migration: Threaded comments on events
+-- root_id is NULL for top-level comments; replies point at the top-level comment of their thread
+
+CREATE TABLE IF NOT EXISTS event_comments (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    parent_id UUID,
+    root_id UUID,
+    content TEXT NOT NULL,
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_comment ON event_comments(event_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_event_comments_root_id ON event_comments(root_id);
+CREATE INDEX IF NOT EXISTS idx_event_comments_deleted_at ON event_comments(deleted_at);
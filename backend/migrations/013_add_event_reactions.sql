@@ -0,0 +1,11 @@
+-- Migration: Emoji reactions on events, one row per (event, user, emoji)
+
+CREATE TABLE IF NOT EXISTS event_reactions (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_reaction ON event_reactions(event_id, user_id, emoji);
@@ -0,0 +1,17 @@
+-- Migration: @mentions in event content and comments
+-- comment_id is NULL when the mention is in the event content itself
+
+CREATE TABLE IF NOT EXISTS mentions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    actor_id UUID NOT NULL REFERENCES users(id),
+    space_id UUID NOT NULL REFERENCES spaces(id),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    comment_id UUID,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_mention_user ON mentions(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_mentions_event_id ON mentions(event_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_comment_id ON mentions(comment_id);
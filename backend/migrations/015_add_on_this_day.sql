@@ -0,0 +1,6 @@
+-- Migration: "On this day" memories and opt-in daily digest email
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS on_this_day_digest BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS digest_sent_on DATE;
+
+CREATE INDEX IF NOT EXISTS idx_events_month_day ON events ((EXTRACT(MONTH FROM event_date)), (EXTRACT(DAY FROM event_date)));
@@ -0,0 +1,17 @@
+-- Migration: Per-space anniversaries with yearly/monthly recurrence and reminder emails
+-- reminded_for records the occurrence a reminder was last sent for, so each occurrence is reminded once
+
+CREATE TABLE IF NOT EXISTS anniversaries (
+    id UUID PRIMARY KEY,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    title VARCHAR(100) NOT NULL,
+    date DATE NOT NULL,
+    recurrence VARCHAR(20) NOT NULL DEFAULT 'yearly',
+    remind_days_before BIGINT,
+    reminded_for DATE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_anniversaries_space_id ON anniversaries(space_id);
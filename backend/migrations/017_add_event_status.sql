@@ -0,0 +1,8 @@
+-- Migration: Draft / scheduled publishing for events
+-- Existing events stay published; scheduled events become visible once publish_at has passed
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_events_status ON events(status);
+CREATE INDEX IF NOT EXISTS idx_events_publish_at ON events(publish_at);
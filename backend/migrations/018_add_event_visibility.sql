@@ -0,0 +1,5 @@
+-- Migration: Per-event visibility within a space
+-- visibility: everyone / only_me / members; visible_to lists the member IDs allowed to see a members-only event
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'everyone';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS visible_to JSONB;
@@ -0,0 +1,7 @@
+-- Migration: Multi-day events
+-- end_date is NULL for single-day events; range queries treat COALESCE(end_date, event_date) as the last day
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS end_date DATE;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS end_time TIME;
+
+CREATE INDEX IF NOT EXISTS idx_events_end_date ON events(end_date);
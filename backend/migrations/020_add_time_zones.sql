@@ -0,0 +1,14 @@
+-- Migration: Time-zone-aware event dates and times
+-- event_date / event_time / end_date / end_time stay local to events.time_zone;
+-- starts_at is the UTC instant of the local start and is used to order timelines across zones
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;
+
+UPDATE events
+SET starts_at = (event_date + COALESCE(event_time, '00:00:00'::time)) AT TIME ZONE time_zone
+WHERE starts_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_starts_at ON events(starts_at);
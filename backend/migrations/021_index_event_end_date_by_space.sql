@@ -0,0 +1,5 @@
+-- Migration: Space-scoped index for multi-day range queries
+-- Range filters use (event_date >= :from OR end_date >= :from) so each branch can use a (space_id, date) index
+
+DROP INDEX IF EXISTS idx_events_end_date;
+CREATE INDEX IF NOT EXISTS idx_space_end_date ON events(space_id, end_date);
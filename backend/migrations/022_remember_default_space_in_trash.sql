@@ -0,0 +1,5 @@
+-- Migration: Remember default spaces across the trash
+-- Moving a space to the trash clears users.default_space_id; space_members.was_default
+-- records who had it as default so restoring the space can put the setting back
+
+ALTER TABLE space_members ADD COLUMN IF NOT EXISTS was_default BOOLEAN NOT NULL DEFAULT FALSE;
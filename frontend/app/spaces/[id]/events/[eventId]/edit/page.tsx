@@ -112,6 +112,7 @@ export default function EditEventPage() {
         .filter((tag) => tag);
 
       await updateEvent(eventId, {
+        version: currentEvent?.version ?? 0,
         title: formData.title,
         description: formData.description || undefined,
         event_date: new Date(formData.event_date).toISOString(),
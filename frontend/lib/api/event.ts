@@ -1,5 +1,5 @@
 import { apiClient } from './client';
-import type { Event, CreateEventRequest, UpdateEventRequest } from '@/types';
+import type { Event, CreateEventRequest, UpdateEventRequest, CursorPage, EventQueryParams } from '@/types';
 
 export const eventApi = {
   // 创建事件
@@ -22,8 +22,8 @@ export const eventApi = {
     return apiClient.delete(`/events/${id}`);
   },
 
-  // 获取空间的事件列表
-  getBySpace: (spaceId: string, params?: { start_date?: string; end_date?: string }) => {
-    return apiClient.get<Event[]>(`/events/spaces/${spaceId}`, { params });
+  // 获取空间的事件列表（游标分页）
+  getBySpace: (spaceId: string, params?: EventQueryParams) => {
+    return apiClient.get<CursorPage<Event>>(`/events/spaces/${spaceId}`, { params });
   },
 };
@@ -1,12 +1,17 @@
 import { create } from 'zustand';
 import { eventApi } from '@/lib/api';
-import type { Event, CreateEventRequest, UpdateEventRequest } from '@/types';
+import type { Event, CreateEventRequest, UpdateEventRequest, EventQueryParams } from '@/types';
+
+const PAGE_SIZE = 100;
 
 interface EventState {
   events: Event[];
   currentEvent: Event | null;
   isLoading: boolean;
-  fetchEvents: (spaceId: string, params?: { start_date?: string; end_date?: string }) => Promise<void>;
+  nextCursor: string | null;
+  hasMore: boolean;
+  fetchEvents: (spaceId: string, params?: EventQueryParams) => Promise<void>;
+  fetchMoreEvents: (spaceId: string, params?: EventQueryParams) => Promise<void>;
   createEvent: (data: CreateEventRequest) => Promise<Event>;
   updateEvent: (id: string, data: UpdateEventRequest) => Promise<void>;
   deleteEvent: (id: string) => Promise<void>;
@@ -14,20 +19,44 @@ interface EventState {
   setCurrentEvent: (event: Event | null) => void;
 }
 
-export const useEventStore = create<EventState>((set) => ({
+export const useEventStore = create<EventState>((set, get) => ({
   events: [],
   currentEvent: null,
   isLoading: false,
+  nextCursor: null,
+  hasMore: false,
 
-  fetchEvents: async (spaceId: string, params?: { start_date?: string; end_date?: string }) => {
+  fetchEvents: async (spaceId: string, params?: EventQueryParams) => {
     set({ isLoading: true });
     try {
-      const response = await eventApi.getBySpace(spaceId, params);
+      const response = await eventApi.getBySpace(spaceId, { limit: PAGE_SIZE, ...params });
+      const page = response.data;
       // 确保返回的是有效数组
-      const events = Array.isArray(response.data) ? response.data.filter(e => e && e.id) : [];
-      set({ events, isLoading: false });
+      const events = Array.isArray(page?.items) ? page.items.filter(e => e && e.id) : [];
+      set({ events, nextCursor: page?.next_cursor ?? null, hasMore: !!page?.has_more, isLoading: false });
     } catch (error) {
-      set({ isLoading: false, events: [] });
+      set({ isLoading: false, events: [], nextCursor: null, hasMore: false });
+      throw error;
+    }
+  },
+
+  fetchMoreEvents: async (spaceId: string, params?: EventQueryParams) => {
+    const { nextCursor, hasMore } = get();
+    if (!hasMore || !nextCursor) return;
+
+    set({ isLoading: true });
+    try {
+      const response = await eventApi.getBySpace(spaceId, { limit: PAGE_SIZE, ...params, cursor: nextCursor });
+      const page = response.data;
+      const more = Array.isArray(page?.items) ? page.items.filter(e => e && e.id) : [];
+      set((state) => ({
+        events: [...state.events, ...more],
+        nextCursor: page?.next_cursor ?? null,
+        hasMore: !!page?.has_more,
+        isLoading: false,
+      }));
+    } catch (error) {
+      set({ isLoading: false });
       throw error;
     }
   },
@@ -7,6 +7,8 @@ export interface User {
   avatar_url?: string;
   default_space_id?: string | null;
   auth_provider?: string;
+  on_this_day_digest?: boolean;
+  time_zone?: string;
   created_at: string;
   updated_at: string;
 }
@@ -43,6 +45,8 @@ export interface Space {
   invite_code: string;
   invite_link: string;
   type: SpaceType;
+  archived_at?: string | null;
+  unread_count?: number;
   created_at: string;
   updated_at: string;
 }
@@ -56,6 +60,8 @@ export interface CreateSpaceRequest {
 export interface SpaceMember {
   user_id: string;
   username: string;
+  nickname?: string;
+  color?: string;
   email: string;
   avatar?: string;
   role: 'owner' | 'member';
@@ -63,6 +69,27 @@ export interface SpaceMember {
 }
 
 // 事件相关类型
+export interface EventPhoto {
+  id: string;
+  event_id: string;
+  image_url: string;
+  thumbnail_url: string;
+  width: number;
+  height: number;
+  sort_order: number;
+  uploaded_at: string;
+}
+
+export interface ReactionSummary {
+  emoji: string;
+  count: number;
+  reacted_by_me: boolean;
+}
+
+export type EventStatus = 'draft' | 'published' | 'scheduled';
+
+export type EventVisibility = 'everyone' | 'only_me' | 'members';
+
 export interface Event {
   id: string;
   space_id: string;
@@ -71,10 +98,24 @@ export interface Event {
   content?: string;
   description?: string;
   images?: string[];
-  event_date: string;
-  event_time?: string;
+  photos?: EventPhoto[];
+  event_date: string;  // 事件时区的本地日期 YYYY-MM-DD
+  event_time?: string | null;  // 事件时区的本地时间 HH:MM:SS
+  end_date?: string | null;
+  end_time?: string | null;
+  time_zone: string;
+  starts_at: string;
   location?: string;
+  latitude?: number | null;
+  longitude?: number | null;
+  place_name?: string;
   tags?: string[];
+  reactions?: ReactionSummary[];
+  status: EventStatus;
+  publish_at?: string | null;
+  visibility: EventVisibility;
+  visible_to?: string[] | null;
+  version: number;
   created_at: string;
   updated_at: string;
 }
@@ -85,11 +126,18 @@ export interface CreateEventRequest {
   description?: string;
   images?: string[];
   event_date: string;
+  end_date?: string;
+  time_zone?: string;
   location?: string;
   tags?: string[];
+  status?: EventStatus;
+  publish_at?: string;
+  visibility?: EventVisibility;
+  visible_to?: string[];
 }
 
 export interface UpdateEventRequest {
+  version: number;
   title?: string;
   description?: string;
   images?: string[];
@@ -119,6 +167,21 @@ export interface PaginationParams {
   limit?: number;
 }
 
+export interface CursorPage<T> {
+  items: T[];
+  next_cursor?: string;
+  has_more: boolean;
+  total?: number;
+}
+
+export interface EventQueryParams {
+  start_date?: string;
+  end_date?: string;
+  cursor?: string;
+  limit?: number;
+  include_total?: boolean;
+}
+
 export interface PaginatedResponse<T> {
   items: T[];
   total: number;